@@ -2,12 +2,16 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"ctac/pkg/ctac"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
 )
 
@@ -22,13 +26,26 @@ func usage() {
 		ctac analyse	[flags]		Analyse an argument file
 		ctac ignore		[subcmd]	Manage ignore file
 		ctac create		[subcmd]	Create argument file
+		ctac convert	[flags]		Convert an argument file between YAML, JSON and TOML
+		ctac import		[subcmd]	Import arguments from other documents
+		ctac impact		<id> [flags]	List the arguments depending on an assumption
+		ctac stale		[flags]		List stale premises across a directory of arguments
+		ctac matrix		[flags]		Rank the alternatives of an argument on its weighted criteria
 		ctac version				Version
 	
 	Examples:
 		ctac analyse -inputFile file.yaml -outputFile results.md -pretty
 		ctac analyse -inputFile file.yaml -parallel -workers 2 -outputFile results.md -pretty
+		ctac analyse -inputFile file.yaml -minScore 70
+		ctac analyse -inputFile file.yaml -format html -outputFile report.html
+		git show HEAD:decision.yaml | ctac analyse -inputFile -
 		ctac ignore print-template
 		ctac create -filePath myargument.yaml
+		ctac convert -inputFile myargument.yaml -outputFile myargument.toml
+		ctac import adr -inputFile docs/adr/0003-queue.md -outputFile 0003-queue.yaml
+		ctac impact GROWTH_20 -dir decisions
+		ctac stale -dir decisions -maxAgeDays 180
+		ctac matrix -inputFile database.yaml
 		ctac version
 
 	Run "ctac <command> -h" for more information about a command.`)
@@ -154,13 +171,18 @@ func analyseCmd(args []string) {
 	flagSet := flag.NewFlagSet("analyse", flag.ContinueOnError)
 	flagSet.SetOutput(os.Stderr)
 
-	inputFile := flagSet.String("inputFile", "", "Path to input argument yaml file")
+	inputFile := flagSet.String("inputFile", "", "Path to input argument file (yaml, json, toml or markdown), or - to read standard input")
 	parallel := flagSet.Bool("parallel", false, "Run rules in parallel (default: false)")
 	workers := flagSet.Int("workers", 3, "Max concurrent workers (only used with parallel flag set as true)")
-	outputFile := flagSet.String("outputFile", "", "Path to results JSON file")
+	outputFile := flagSet.String("outputFile", "", "Path to results file")
+	format := flagSet.String("format", "json", "Format of the results file: json, json-reports, markdown or html")
 	pretty := flagSet.Bool("pretty", false, "Pretty-print JSON")
 	silent := flagSet.Bool("silent", false, "Quiet mode to silence output written to standard out")
 	ignoreFile := flagSet.String("ignoreFile", "", "Path to ignore file")
+	configFile := flagSet.String("configFile", "", "Path to config file")
+	trustConfig := flagSet.Bool("trustConfig", false, "Run the plugins and processRules of a config file found in the working directory")
+	ruleTimeout := flagSet.Duration("ruleTimeout", 0, "Maximum time each rule may run, e.g. 2s (default: no limit)")
+	minScore := flagSet.Int("minScore", 0, "Exit with status 1 when an argument scores below this (0-100)")
 
 	if err := flagSet.Parse(args); err != nil {
 		if err == flag.ErrHelp {
@@ -174,53 +196,427 @@ func analyseCmd(args []string) {
 	if *inputFile == "" {
 		log.Fatalf("error: -inputFile is required")
 	}
+	if *minScore < 0 || *minScore > 100 {
+		log.Fatalf("error: -minScore must be between 0 and 100, got %d", *minScore)
+	}
+	reportFormat, err := ctac.ParseReportFormat(*format)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
 
-	argument, err := ctac.Loader(*inputFile)
+	arguments, err := ctac.LoadArguments(*inputFile)
 	if err != nil {
 		log.Fatalf("load input error: %v", err)
 	}
 
 	if !*silent {
 		fmt.Println("Welcome to ctac, critical thinking as code")
-		fmt.Println(ctac.SummariseArgument(*argument))
 	}
 
-	var issues []ctac.Issue
+	config, err := loadConfig(*configFile, *trustConfig)
+	if err != nil {
+		log.Fatalf("Load config file error: %v", err)
+	}
+	defer config.Close()
+
+	ignoreSpec, err := ctac.LoadIgnore(*ignoreFile)
+	if err != nil {
+		log.Fatalf("Load ignore file error: %v", err)
+	}
+
+	options := []ctac.Option{ctac.WithConfig(config), ctac.WithIgnore(ignoreSpec), ctac.WithRuleTimeout(*ruleTimeout)}
 	if *parallel {
 		if !*silent {
 			fmt.Println("Running all rules in parallel")
 		}
-		issues = ctac.RunAllRulesParallel(*argument, *workers)
+		options = append(options, ctac.WithParallelism(*workers))
+	}
+	analyzer := ctac.NewAnalyzer(options...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var reports []ctac.Report
+	for _, argument := range arguments {
+		if !*silent {
+			if len(arguments) > 1 {
+				fmt.Printf("==============\nArgument %d of %d (line %d)\n", argument.Index+1, len(arguments), argument.Line)
+			}
+			fmt.Println(ctac.SummariseArgument(argument))
+		}
+
+		report := analyzer.Analyze(ctx, argument)
+		for _, ruleErr := range report.RuleErrors {
+			log.Printf("error: %v", ruleErr)
+		}
+		reports = append(reports, report)
+
+		if !*silent {
+			fmt.Println(ctac.FormatIssueMessage(argument, report, ctac.ColorEnabled(os.Stdout)))
+		}
+	}
+	if *outputFile != "" {
+		var b bytes.Buffer
+		switch reportFormat {
+		case ctac.ReportMarkdown:
+			err = ctac.WriteMarkdownReport(&b, arguments, reports)
+		case ctac.ReportHTML:
+			err = ctac.WriteHTMLReport(&b, arguments, reports)
+		default:
+			encoder := json.NewEncoder(&b)
+			if *pretty {
+				encoder.SetIndent("", "  ")
+			}
+			if reportFormat == ctac.ReportJSONReports {
+				err = encoder.Encode(reports)
+				break
+			}
+			var issues []ctac.Issue
+			for _, report := range reports {
+				issues = append(issues, report.Issues...)
+			}
+			err = encoder.Encode(issues)
+		}
+		if err != nil {
+			log.Fatalf("error writing %s report: %v", reportFormat, err)
+		}
+		if err := os.WriteFile(*outputFile, b.Bytes(), 0o644); err != nil {
+			log.Fatalf("Write outputfile: %v", err)
+		}
+	}
+
+	failed := 0
+	for _, report := range reports {
+		if len(report.RuleErrors) > 0 {
+			log.Printf("%q was not fully checked because some rules failed", report.Title)
+			failed++
+		}
+		if report.Score < *minScore {
+			log.Printf("%q scores %d, below the minimum of %d", report.Title, report.Score, *minScore)
+			failed++
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func convertCmd(args []string) {
+	flagSet := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flagSet.SetOutput(os.Stderr)
+
+	inputFile := flagSet.String("inputFile", "", "Path to input argument file (yaml, json, toml or markdown), or - to read standard input")
+	outputFile := flagSet.String("outputFile", "", "Path to converted argument file (default: standard out)")
+	to := flagSet.String("to", "", "Output format: yaml, json or toml (default: from the -outputFile extension)")
+
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
+	}
+
+	log.SetFlags(0)
+
+	if *inputFile == "" {
+		log.Fatalf("error: -inputFile is required")
+	}
+
+	var format ctac.Format
+	if *to != "" {
+		var err error
+		if format, err = ctac.ParseFormat(*to); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	} else if outputFormat, ok := ctac.FormatFromPath(*outputFile); ok {
+		format = outputFormat
 	} else {
-		issues = ctac.RunAllRulesSequential(*argument)
+		log.Fatalf("error: -to is required when -outputFile has no yaml, json or toml extension")
 	}
 
-	var filteredIssues []ctac.Issue
-	ignoreSpec, err := ctac.LoadIgnore(*ignoreFile)
+	arguments, err := ctac.LoadArguments(*inputFile)
 	if err != nil {
-		log.Fatalf("Load ignore file error: %v", err)
+		log.Fatalf("load input error: %v", err)
+	}
+	data, err := ctac.EncodeArguments(arguments, format)
+	if err != nil {
+		log.Fatalf("error encoding %s: %v", format, err)
+	}
+
+	if *outputFile == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*outputFile, data, 0o644); err != nil {
+		log.Fatalf("Write outputfile: %v", err)
+	}
+}
+
+func importCmd(args []string) {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		fmt.Println(`Usage:
+  ctac import adr [flags]   # build an argument from the sections of a Markdown ADR`)
+		return
+	}
+	switch args[0] {
+	case "adr":
+		importADRCmd(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown import subcommand %q\n", args[0])
+		os.Exit(2)
+	}
+}
+
+func importADRCmd(args []string) {
+	flagSet := flag.NewFlagSet("import adr", flag.ContinueOnError)
+	flagSet.SetOutput(os.Stderr)
+
+	inputFile := flagSet.String("inputFile", "", "Path to Markdown ADR, or - to read standard input")
+	outputFile := flagSet.String("outputFile", "", "Path to argument file to write (default: standard out)")
+	to := flagSet.String("to", "", "Output format: yaml, json or toml (default: from the -outputFile extension, or yaml)")
+	configFile := flagSet.String("configFile", "", "Path to config file with the adr heading mappings")
+
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
 	}
 
-	ignored := make(map[string]bool, len(ignoreSpec.Rules))
-	for _, rule := range ignoreSpec.Rules {
-		ignored[rule] = true
+	log.SetFlags(0)
+
+	if *inputFile == "" {
+		log.Fatalf("error: -inputFile is required")
 	}
-	for _, issue := range issues {
-		fmt.Printf("Processing issue %v with rule id %s", issue, issue.RuleID)
-		if _, skip := ignored[issue.RuleID]; !skip {
-			filteredIssues = append(filteredIssues, issue)
+
+	format := ctac.FormatYAML
+	if *to != "" {
+		var err error
+		if format, err = ctac.ParseFormat(*to); err != nil {
+			log.Fatalf("error: %v", err)
 		}
+	} else if outputFormat, ok := ctac.FormatFromPath(*outputFile); ok {
+		format = outputFormat
 	}
 
-	if !*silent {
-		fmt.Println(ctac.FormatIssueMessage(filteredIssues))
+	config, err := ctac.LoadConfigSettings(*configFile)
+	if err != nil {
+		log.Fatalf("Load config file error: %v", err)
+	}
+	defer config.Close()
+
+	var data []byte
+	if *inputFile == ctac.StdinPath {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*inputFile)
+	}
+	if err != nil {
+		log.Fatalf("read input error: %v", err)
+	}
+
+	argument, err := ctac.ImportADR(data, config.ADR)
+	if err != nil {
+		log.Fatalf("import ADR error: %v", err)
+	}
+	encoded, err := ctac.EncodeArgument(argument, format)
+	if err != nil {
+		log.Fatalf("error encoding %s: %v", format, err)
+	}
+
+	if *outputFile == "" {
+		os.Stdout.Write(encoded)
+		return
 	}
+	if err := os.WriteFile(*outputFile, encoded, 0o644); err != nil {
+		log.Fatalf("Write outputfile: %v", err)
+	}
+}
+
+func impactCmd(args []string) {
+	flagSet := flag.NewFlagSet("impact", flag.ContinueOnError)
+	flagSet.SetOutput(os.Stderr)
+	flagSet.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ctac impact <assumption-id> [flags]")
+		flagSet.PrintDefaults()
+	}
+
+	dir := flagSet.String("dir", ".", "Directory of argument files to search")
+	configFile := flagSet.String("configFile", "", "Path to config file")
+	assumptionsFile := flagSet.String("assumptionsFile", "", "Path to assumptions file (default: from the config file, or assumptions.yaml)")
+
+	var assumptionID string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		assumptionID, args = args[0], args[1:]
+	}
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
+	}
+
+	log.SetFlags(0)
+
+	if assumptionID == "" {
+		assumptionID = flagSet.Arg(0)
+	}
+	if assumptionID == "" {
+		flagSet.Usage()
+		os.Exit(2)
+	}
+
+	config, err := ctac.LoadConfigSettings(*configFile)
+	if err != nil {
+		log.Fatalf("Load config file error: %v", err)
+	}
+	defer config.Close()
+	registry := config.Assumptions()
+	if *assumptionsFile != "" {
+		if registry, err = ctac.LoadAssumptions(*assumptionsFile, "."); err != nil {
+			log.Fatalf("Load assumptions file error: %v", err)
+		}
+	}
+
+	if assumption, ok := registry.Get(assumptionID); ok {
+		fmt.Printf("Assumption %s: %s\nOwner: %s | Confidence: %s | Review date: %s\n\n", assumption.ID, assumption.Text, assumption.Owner, assumption.Confidence, assumption.ReviewDate)
+	} else {
+		log.Printf("warning: assumption %s is not in the assumptions registry", assumptionID)
+	}
+
+	files, errs := ctac.LoadDir(*dir)
+	for _, err := range errs {
+		log.Printf("warning: %v", err)
+	}
+
+	var lines []string
+	for _, file := range files {
+		dependency, ok := ctac.DependsOn(file.Argument, assumptionID)
+		if !ok {
+			continue
+		}
+		var via []string
+		if dependency.Direct {
+			via = append(via, "the argument")
+		}
+		if len(dependency.Premises) > 0 {
+			via = append(via, "premises "+strings.Join(dependency.Premises, ", "))
+		}
+		lines = append(lines, fmt.Sprintf("- %s:%d | %s | cited by %s\n", file.Path, file.Argument.Line, file.Argument.Title, strings.Join(via, " and ")))
+	}
+
+	if len(lines) == 0 {
+		fmt.Printf("No arguments depend on %s.\n", assumptionID)
+		return
+	}
+	fmt.Printf("%d argument%s depend%s on %s:\n\n%s", len(lines), plural(len(lines)), pluralVerb(len(lines)), assumptionID, strings.Join(lines, ""))
+}
+
+func staleCmd(args []string) {
+	flagSet := flag.NewFlagSet("stale", flag.ContinueOnError)
+	flagSet.SetOutput(os.Stderr)
+
+	dir := flagSet.String("dir", ".", "Directory of argument files to search")
+	configFile := flagSet.String("configFile", "", "Path to config file")
+	maxAgeDays := flagSet.Int("maxAgeDays", 0, "Report evidence older than this many days (default: freshness.maxAgeDays from the config file, or 365)")
+
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
+	}
+
+	log.SetFlags(0)
+
+	if flagSet.NArg() > 0 {
+		log.Fatalf("error: unexpected argument %q; use -dir to choose the directory to search", flagSet.Arg(0))
+	}
+
+	config, err := ctac.LoadConfigSettings(*configFile)
+	if err != nil {
+		log.Fatalf("Load config file error: %v", err)
+	}
+	defer config.Close()
+	if *maxAgeDays == 0 {
+		*maxAgeDays = config.Freshness.MaxAgeDays
+	}
+
+	files, errs := ctac.LoadDir(*dir)
+	for _, err := range errs {
+		log.Printf("warning: %v", err)
+	}
+
+	count := 0
+	for _, file := range files {
+		for _, stale := range ctac.FindStaleEvidence(file.Argument, *maxAgeDays, ctac.Date{}) {
+			where := file.Path
+			if stale.Line > 0 {
+				where = fmt.Sprintf("%s:%d", file.Path, stale.Line)
+			}
+			fmt.Printf("- %s | %s | %s\n", where, file.Argument.Title, stale)
+			count++
+		}
+	}
+	if count == 0 {
+		fmt.Println("✅ No stale premises found.")
+		return
+	}
+	fmt.Printf("\nFound %d stale premise%s or source%s.\n", count, plural(count), plural(count))
+}
+
+func matrixCmd(args []string) {
+	flagSet := flag.NewFlagSet("matrix", flag.ContinueOnError)
+	flagSet.SetOutput(os.Stderr)
+
+	inputFile := flagSet.String("inputFile", "", "Path to input argument file (yaml, json, toml or markdown), or - to read standard input")
+	outputFile := flagSet.String("outputFile", "", "Path to results JSON file")
+	pretty := flagSet.Bool("pretty", false, "Pretty-print JSON")
+
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
+	}
+
+	log.SetFlags(0)
+
+	if *inputFile == "" {
+		log.Fatalf("error: -inputFile is required")
+	}
+
+	arguments, err := ctac.LoadArguments(*inputFile)
+	if err != nil {
+		log.Fatalf("load input error: %v", err)
+	}
+
+	type matrixResult struct {
+		Title  string
+		Index  int
+		Matrix *ctac.Matrix
+	}
+	var results []matrixResult
+	for _, argument := range arguments {
+		matrix, err := ctac.NewMatrix(argument)
+		if err != nil {
+			if len(arguments) == 1 {
+				log.Fatalf("error: %v", err)
+			}
+			log.Printf("skipping argument %d (%q): %v", argument.Index+1, argument.Title, err)
+			continue
+		}
+		results = append(results, matrixResult{Title: argument.Title, Index: argument.Index, Matrix: matrix})
+
+		fmt.Printf("%s\n\n%s\n", argument.Title, ctac.FormatMatrix(matrix))
+	}
+
 	if *outputFile != "" {
 		var b []byte
 		if *pretty {
-			b, err = json.MarshalIndent(filteredIssues, "", "  ")
+			b, err = json.MarshalIndent(results, "", "  ")
 		} else {
-			b, err = json.Marshal(filteredIssues)
+			b, err = json.Marshal(results)
 		}
 		if err != nil {
 			log.Fatalf("error encoding JSON: %v", err)
@@ -231,6 +627,20 @@ func analyseCmd(args []string) {
 	}
 }
 
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+func pluralVerb(n int) string {
+	if n == 1 {
+		return "s"
+	}
+	return ""
+}
+
 func ignoreCmd(args []string) {
 	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
 		fmt.Println(`Usage:
@@ -263,6 +673,16 @@ func main() {
 		createCmd(os.Args[2:])
 	case "analyze", "analyse", "-a":
 		analyseCmd(os.Args[2:])
+	case "convert":
+		convertCmd(os.Args[2:])
+	case "import":
+		importCmd(os.Args[2:])
+	case "impact":
+		impactCmd(os.Args[2:])
+	case "stale":
+		staleCmd(os.Args[2:])
+	case "matrix":
+		matrixCmd(os.Args[2:])
 	case "ignore", "-i":
 		ignoreCmd(os.Args[2:])
 	case "help", "-h", "--help", "man":
@@ -274,3 +694,12 @@ func main() {
 		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
 	}
 }
+
+// loadConfig loads the config file, trusting one found in the working
+// directory to run plugins and process rules only when trust is set.
+func loadConfig(path string, trust bool) (*ctac.Config, error) {
+	if trust {
+		return ctac.LoadTrustedConfig(path)
+	}
+	return ctac.LoadConfig(path)
+}
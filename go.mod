@@ -2,4 +2,8 @@ module ctac
 
 go 1.25.0
 
-require gopkg.in/yaml.v3 v3.0.1 // indirect
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/tetratelabs/wazero v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
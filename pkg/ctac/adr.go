@@ -0,0 +1,150 @@
+package ctac
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ADRMapping maps the section headings of a Markdown ADR to an argument.
+// Headings are matched case-insensitively at any level; empty lists use the
+// defaults.
+type ADRMapping struct {
+	// Premises are the sections whose bullet points, or paragraphs when
+	// there are none, become premises.
+	Premises []string `yaml:"premises" json:"premises,omitempty"`
+	// Conclusion are the sections whose first bullet point or paragraph
+	// becomes the conclusion.
+	Conclusion []string `yaml:"conclusion" json:"conclusion,omitempty"`
+}
+
+var defaultADRMapping = ADRMapping{
+	Premises:   []string{"Context", "Context and Problem Statement", "Decision Drivers"},
+	Conclusion: []string{"Decision", "Decision Outcome"},
+}
+
+var (
+	regexHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	regexBullet  = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+)
+
+// adrBlock is a bullet point or paragraph and the line it starts on.
+type adrBlock struct {
+	text string
+	line int
+}
+
+// ImportADR builds an argument from the sections of a Markdown ADR. The
+// title is the first level-one heading. Premises are numbered P1, P2, …
+// and, like the conclusion, get a medium confidence and a should modality
+// for the author to review. Lines refer to the Markdown document.
+func ImportADR(data []byte, mapping ADRMapping) (*Argument, error) {
+	if len(mapping.Premises) == 0 {
+		mapping.Premises = defaultADRMapping.Premises
+	}
+	if len(mapping.Conclusion) == 0 {
+		mapping.Conclusion = defaultADRMapping.Conclusion
+	}
+
+	argument := Argument{Line: 1}
+	var premises, conclusion []adrBlock
+	var section *[]adrBlock
+	var current *adrBlock
+	sectionStart := 0
+	bullets := false
+	inFence := false
+
+	flush := func() {
+		if current != nil && section != nil {
+			*section = append(*section, *current)
+		}
+		current = nil
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		lineNumber := i + 1
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		if heading := regexHeading.FindStringSubmatch(trimmed); heading != nil {
+			flush()
+			if len(heading[1]) == 1 && argument.Title == "" {
+				argument.Title = heading[2]
+			}
+			section, bullets = nil, false
+			switch {
+			case headingIn(heading[2], mapping.Premises):
+				section = &premises
+			case headingIn(heading[2], mapping.Conclusion):
+				section = &conclusion
+			}
+			if section != nil {
+				sectionStart = len(*section)
+			}
+			continue
+		}
+		if section == nil {
+			continue
+		}
+
+		if bullet := regexBullet.FindStringSubmatch(line); bullet != nil {
+			flush()
+			if !bullets {
+				// The first bullet point drops the paragraphs before it,
+				// which usually introduce the list.
+				*section = (*section)[:sectionStart]
+				bullets = true
+			}
+			current = &adrBlock{text: bullet[1], line: lineNumber}
+			continue
+		}
+		switch {
+		case trimmed == "":
+			flush()
+		case current != nil:
+			current.text += " " + trimmed
+		case !bullets:
+			current = &adrBlock{text: trimmed, line: lineNumber}
+		}
+	}
+	flush()
+
+	if len(premises) == 0 && len(conclusion) == 0 {
+		return nil, fmt.Errorf("ADR has none of the sections %s", strings.Join(append(mapping.Premises, mapping.Conclusion...), ", "))
+	}
+
+	for i, block := range premises {
+		argument.Premises = append(argument.Premises, Premise{
+			Id:         fmt.Sprintf("P%d", i+1),
+			Text:       block.text,
+			Confidence: Medium,
+			Line:       block.line,
+		})
+	}
+	if len(conclusion) > 0 {
+		argument.Conclusion = Conclusion{
+			Text:       conclusion[0].text,
+			Modality:   ModalityShould,
+			Confidence: Medium,
+			Line:       conclusion[0].line,
+		}
+	}
+	return &argument, nil
+}
+
+func headingIn(heading string, headings []string) bool {
+	for _, h := range headings {
+		if strings.EqualFold(strings.TrimSpace(h), heading) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package ctac
+
+import (
+	"strings"
+	"testing"
+)
+
+const sectionADR = `# ADR 3: Use a message queue
+
+## Status
+
+Accepted
+
+## Context
+
+Services call each other synchronously.
+We need to decouple them:
+
+- Order spikes overload the billing service
+  during sales.
+- Retries are implemented three times
+
+` + "```" + `
+- not a premise
+` + "```" + `
+
+## Decision
+
+We will put a queue between orders and billing.
+
+## Consequences
+
+- Billing becomes eventually consistent
+`
+
+func TestImportADR(t *testing.T) {
+
+	cases := []struct {
+		name           string
+		mapping        ADRMapping
+		wantPremises   []string
+		wantLines      []int
+		wantConclusion string
+		wantErr        string
+	}{
+		{
+			name:           "Default headings",
+			wantPremises:   []string{"Order spikes overload the billing service during sales.", "Retries are implemented three times"},
+			wantLines:      []int{12, 14},
+			wantConclusion: "We will put a queue between orders and billing.",
+		},
+		{
+			name:           "Custom headings",
+			mapping:        ADRMapping{Premises: []string{"context", "Consequences"}, Conclusion: []string{"Decision"}},
+			wantPremises:   []string{"Order spikes overload the billing service during sales.", "Retries are implemented three times", "Billing becomes eventually consistent"},
+			wantLines:      []int{12, 14, 26},
+			wantConclusion: "We will put a queue between orders and billing.",
+		},
+		{
+			name:           "Paragraphs become premises without bullet points",
+			mapping:        ADRMapping{Premises: []string{"Status"}},
+			wantPremises:   []string{"Accepted"},
+			wantLines:      []int{5},
+			wantConclusion: "We will put a queue between orders and billing.",
+		},
+		{
+			name:    "No mapped sections",
+			mapping: ADRMapping{Premises: []string{"Background"}, Conclusion: []string{"Outcome"}},
+			wantErr: "none of the sections",
+		},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			argument, err := ImportADR([]byte(sectionADR), tc.mapping)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("importing: %v", err)
+			}
+			if argument.Title != "ADR 3: Use a message queue" {
+				t.Fatalf("got title %q", argument.Title)
+			}
+			if len(argument.Premises) != len(tc.wantPremises) {
+				t.Fatalf("got premises %+v but we wanted %q", argument.Premises, tc.wantPremises)
+			}
+			for i, premise := range argument.Premises {
+				if premise.Text != tc.wantPremises[i] || premise.Line != tc.wantLines[i] {
+					t.Fatalf("got premise %q on line %d but we wanted %q on line %d", premise.Text, premise.Line, tc.wantPremises[i], tc.wantLines[i])
+				}
+			}
+			if argument.Conclusion.Text != tc.wantConclusion {
+				t.Fatalf("got conclusion %q but we wanted %q", argument.Conclusion.Text, tc.wantConclusion)
+			}
+			if err := argument.Validate(); err != nil {
+				t.Fatalf("imported argument is invalid: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package ctac
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFalseDilemmaRule(t *testing.T) {
+
+	rule := FalseDilemmaRule{}
+	premises := []Premise{
+		{Id: "P1", Text: "The current database is at 80% capacity", Confidence: High},
+		{Id: "P2", Text: "Growth is 5% per month", Confidence: Medium},
+	}
+	cases := TestCases{
+		{
+			name:       "No alternatives and no either/or framing",
+			argument:   Argument{Title: "Test", Premises: premises},
+			wantIssues: 0,
+		},
+		{
+			name: "Exactly two alternatives",
+			argument: Argument{
+				Title:        "Test",
+				Premises:     premises,
+				Alternatives: []Alternative{{Name: "Shard"}, {Name: "Migrate"}},
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "Three alternatives",
+			argument: Argument{
+				Title:        "Test",
+				Premises:     premises,
+				Alternatives: []Alternative{{Name: "Shard"}, {Name: "Migrate"}, {Name: "Archive old data"}},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "Either/or framing in a premise",
+			argument: Argument{
+				Title: "Test",
+				Premises: []Premise{
+					{Id: "P1", Text: "Either we migrate this quarter or we lose our biggest customer", Confidence: Medium},
+					{Id: "P2", Text: "Migrating is the only option left", Confidence: Medium},
+				},
+			},
+			wantIssues: 2,
+		},
+		{
+			name: "Negated framing is not reported",
+			argument: Argument{
+				Title:    "Test",
+				Premises: []Premise{{Id: "P1", Text: "Migrating is not the only option", Confidence: Medium}},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "Quoted framing is not reported",
+			argument: Argument{
+				Title:    "Test",
+				Premises: []Premise{{Id: "P1", Text: `The vendor claims "there is no alternative" to their product`, Confidence: Medium}},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "Italian framing",
+			argument: Argument{
+				Title:    "Test",
+				Language: "it",
+				Premises: []Premise{{Id: "P1", Text: "Non c'è alternativa alla migrazione", Confidence: Medium}},
+			},
+			wantIssues: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := rule.Check(tc.argument)
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing argument %q: got %d issue%s but we wanted %d: %v", tc.argument.Title, got, plural(got), tc.wantIssues, issues)
+			}
+		})
+	}
+}
+
+func TestMustWithoutAlternativesRule(t *testing.T) {
+
+	rule := MustWithoutAlternativesRule{}
+	cases := TestCases{
+		{
+			name:       "Must without alternatives",
+			argument:   Argument{Title: "Test", Conclusion: Conclusion{Text: "We must migrate", Modality: ModalityMust}},
+			wantIssues: 1,
+		},
+		{
+			name: "Must with alternatives",
+			argument: Argument{
+				Title:        "Test",
+				Conclusion:   Conclusion{Text: "We must migrate", Modality: ModalityMust},
+				Alternatives: []Alternative{{Name: "Stay", RejectedBecause: "We run out of capacity in June"}},
+			},
+			wantIssues: 0,
+		},
+		{
+			name:       "Should without alternatives",
+			argument:   Argument{Title: "Test", Conclusion: Conclusion{Text: "We should migrate", Modality: ModalityShould}},
+			wantIssues: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := rule.Check(tc.argument)
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing argument %q: got %d issue%s but we wanted %d", tc.argument.Title, got, plural(got), tc.wantIssues)
+			}
+		})
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+
+	data := `title: Database
+premises:
+  - id: P1
+    text: The database is at 80% capacity
+    confidence: high
+alternatives:
+  - name: Shard
+    pros:
+      - text: Keeps the current engine
+        confidence: high
+    cons:
+      - text: Needs application changes
+        confidence: medium
+    rejectedBecause: Too much application work
+  - name: Migrate
+conclusion:
+  text: We should migrate
+  modality: should
+  confidence: medium
+`
+	argument, err := DecodeArgument([]byte(data), FormatYAML)
+	if err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	shard := argument.Alternatives[0]
+	if shard.Line != 7 || shard.Pros[0].Line != 9 || shard.Cons[0].Line != 12 || argument.Alternatives[1].Line != 15 {
+		t.Errorf("got lines %d, %d, %d and %d, want 7, 9, 12 and 15", shard.Line, shard.Pros[0].Line, shard.Cons[0].Line, argument.Alternatives[1].Line)
+	}
+
+	for _, format := range Formats {
+		encoded, err := EncodeArgument(argument, format)
+		if err != nil {
+			t.Fatalf("%s: encoding: %v", format, err)
+		}
+		decoded, err := DecodeArgument(encoded, format)
+		if err != nil {
+			t.Fatalf("%s: decoding: %v", format, err)
+		}
+		if got := decoded.Alternatives; len(got) != 2 || got[0].RejectedBecause != shard.RejectedBecause || got[0].Cons[0].Text != shard.Cons[0].Text {
+			t.Errorf("%s: alternatives did not round-trip: %+v", format, got)
+		}
+	}
+
+	invalid := strings.Replace(data, "- name: Migrate", "- name: Shard\n    cons:\n      - confidence: sure", 1)
+	_, err = DecodeArgument([]byte(invalid), FormatYAML)
+	want := []string{
+		`alternatives[1]: duplicate name "Shard"`,
+		"alternatives[1].cons[0]: text is required",
+		`alternatives[1].cons[0]: unknown confidence "sure"`,
+	}
+	for _, w := range want {
+		if err == nil || !strings.Contains(err.Error(), w) {
+			t.Errorf("got error %v, want it to contain %q", err, w)
+		}
+	}
+}
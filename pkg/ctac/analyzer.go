@@ -0,0 +1,230 @@
+package ctac
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Analyzer runs rules against arguments and filters the results through an
+// ignore spec. It is what `ctac analyse` uses, so Go programs embedding ctac
+// get the same results as the CLI. Build one with NewAnalyzer.
+type Analyzer struct {
+	rules       []Rule
+	config      *Config
+	ignore      *IgnoreSpec
+	workers     int
+	ruleTimeout time.Duration
+	overrides   map[string]Severity
+	severities  map[string]Severity
+	weights     ScoreWeights
+}
+
+// Option configures an Analyzer.
+type Option func(*Analyzer)
+
+// WithRules sets the rules to run, replacing the rules of the config.
+func WithRules(rules ...Rule) Option {
+	return func(a *Analyzer) {
+		a.rules = rules
+	}
+}
+
+// WithConfig sets a compiled config. Unless WithRules is given, the analyzer
+// runs the config's built-in, custom and plugin rules, and applies its
+// severity overrides.
+func WithConfig(config *Config) Option {
+	return func(a *Analyzer) {
+		a.config = config
+	}
+}
+
+// WithIgnore sets the ignore spec used to suppress issues.
+func WithIgnore(spec *IgnoreSpec) Option {
+	return func(a *Analyzer) {
+		a.ignore = spec
+	}
+}
+
+// WithParallelism runs the rules on up to workers goroutines, or on one
+// goroutine per rule when workers is zero or less.
+func WithParallelism(workers int) Option {
+	return func(a *Analyzer) {
+		a.workers = workers
+		if workers <= 0 {
+			a.workers = -1
+		}
+	}
+}
+
+// WithRuleTimeout limits how long each rule may run.
+func WithRuleTimeout(timeout time.Duration) Option {
+	return func(a *Analyzer) {
+		a.ruleTimeout = timeout
+	}
+}
+
+// WithScoreWeights sets the weights of the parts of the score, replacing
+// those of the config.
+func WithScoreWeights(weights ScoreWeights) Option {
+	return func(a *Analyzer) {
+		a.weights = weights
+	}
+}
+
+// WithSeverityOverride reports the issues of a rule with the given severity.
+// It takes precedence over the overrides of the config.
+func WithSeverityOverride(ruleID string, severity Severity) Option {
+	return func(a *Analyzer) {
+		if a.overrides == nil {
+			a.overrides = make(map[string]Severity)
+		}
+		a.overrides[ruleID] = severity
+	}
+}
+
+// NewAnalyzer builds an analyzer. Without options it runs the built-in rules
+// sequentially and suppresses nothing.
+func NewAnalyzer(opts ...Option) *Analyzer {
+	analyzer := &Analyzer{}
+	for _, opt := range opts {
+		opt(analyzer)
+	}
+
+	if analyzer.rules == nil {
+		analyzer.rules = ConfiguredRules(analyzer.config)
+	}
+	if analyzer.workers < 0 {
+		analyzer.workers = len(analyzer.rules)
+	}
+	if analyzer.ignore == nil {
+		analyzer.ignore = &IgnoreSpec{}
+	}
+
+	analyzer.severities = make(map[string]Severity)
+	if analyzer.config != nil {
+		if analyzer.weights == (ScoreWeights{}) {
+			analyzer.weights = analyzer.config.Score.Weights
+		}
+		for ruleID, severity := range analyzer.config.Severities {
+			analyzer.severities[ruleID] = severity
+		}
+	}
+	for ruleID, severity := range analyzer.overrides {
+		analyzer.severities[ruleID] = severity
+	}
+	return analyzer
+}
+
+// Rules returns the rules the analyzer runs.
+func (a *Analyzer) Rules() []Rule {
+	return a.rules
+}
+
+// SuppressedIssue is an issue hidden by the ignore spec.
+type SuppressedIssue struct {
+	Issue
+	Reason string `json:",omitempty"`
+}
+
+// RuleTiming is how long a rule took to run.
+type RuleTiming struct {
+	RuleID   string
+	Duration time.Duration
+}
+
+// IssueRule is a rule that checks the issues the other rules found, after
+// severity overrides and ignores. The Analyzer runs it after every other
+// rule.
+type IssueRule interface {
+	Rule
+	CheckIssues(a Argument, issues []Issue) []Issue
+}
+
+// Report is the result of analysing an argument. Index and Line locate
+// the argument in its file. Score rates the argument from 0 to 100 (see
+// ScoreArgument) and Grade turns it into a letter. Ignored lists every rule
+// of the ignore spec, including those that suppressed nothing.
+type Report struct {
+	Title      string
+	Metadata   Metadata
+	Index      int
+	Line       int
+	Issues     []Issue
+	Suppressed []SuppressedIssue
+	Ignored    []IgnoredRule `json:",omitempty"`
+	RuleErrors []*RuleError
+	Timings    []RuleTiming
+	Duration   time.Duration
+	Score      int
+	Grade      string
+}
+
+// AnalyzeAll analyses each argument of a file in turn.
+func (a *Analyzer) AnalyzeAll(ctx context.Context, arguments []Argument) []Report {
+	reports := make([]Report, 0, len(arguments))
+	for _, argument := range arguments {
+		reports = append(reports, a.Analyze(ctx, argument))
+	}
+	return reports
+}
+
+// Analyze runs the rules against the argument. Rules that fail are listed in
+// the report's RuleErrors rather than stopping the analysis.
+func (a *Analyzer) Analyze(ctx context.Context, argument Argument) Report {
+	start := time.Now()
+	engine := Engine{Rules: a.rules, MaxWorkers: a.workers, RuleTimeout: a.ruleTimeout, skipIssueRules: true}
+
+	report := Report{Title: argument.Title, Metadata: argument.Metadata, Index: argument.Index, Line: argument.Line, Ignored: a.ignore.IgnoredRules()}
+	ref := &ArgumentRef{Index: argument.Index, Title: argument.Title, Line: argument.Line}
+	add := func(issues []Issue) {
+		for _, issue := range issues {
+			if issue.Line == 0 {
+				issue.Line = argument.Line
+			}
+			issue.Argument = ref
+			if severity, ok := a.severities[issue.RuleID]; ok {
+				issue.Severity = severity
+			}
+			if reason, ignored := a.ignore.Ignores(issue.RuleID); ignored {
+				report.Suppressed = append(report.Suppressed, SuppressedIssue{Issue: issue, Reason: reason})
+				continue
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	for _, result := range engine.RunRules(ctx, argument) {
+		report.Timings = append(report.Timings, RuleTiming{RuleID: result.RuleID, Duration: result.Duration})
+		if result.Err != nil {
+			report.RuleErrors = append(report.RuleErrors, result.Err)
+		}
+		add(result.Issues)
+	}
+
+	// Issue rules see the issues of every other rule, so they run last,
+	// one after the other, guarded by the engine like any other rule.
+	found := report.Issues
+	for i, rule := range a.rules {
+		if issueRule, ok := rule.(IssueRule); ok {
+			result := engine.runRule(ctx, issueCheck{IssueRule: issueRule, issues: found}, argument)
+			report.Timings[i].Duration += result.Duration
+			if result.Err != nil {
+				report.RuleErrors = append(report.RuleErrors, result.Err)
+			}
+			add(result.Issues)
+		}
+	}
+
+	report.Score = ScoreArgument(argument, report.Issues, a.weights)
+	report.Grade = Grade(report.Score)
+	report.Duration = time.Since(start)
+	return report
+}
+
+func (e *RuleError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RuleID string
+		Error  string
+	}{e.RuleID, e.Err.Error()})
+}
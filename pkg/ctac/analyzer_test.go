@@ -0,0 +1,153 @@
+package ctac
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzer(t *testing.T) {
+
+	argument := Argument{Title: "Analyzer", Premises: []Premise{{Id: "P1", Text: "Rules can fail"}}}
+	rules := []Rule{MissingConclusionRule{}, SinglePremiseRule{}, failingRule{}}
+
+	cases := []struct {
+		name           string
+		options        []Option
+		wantIssues     []string
+		wantSuppressed []string
+		wantSeverity   Severity
+		wantReason     string
+	}{
+		{
+			name:         "Every issue is reported without an ignore spec",
+			options:      []Option{WithRules(rules...)},
+			wantIssues:   []string{"CTAC003_MISSING_CONCLUSION_RULE", "CTAC004_SINGLE_PREMISE_RULE"},
+			wantSeverity: SeverityError,
+		},
+		{
+			name: "Ignored rules are suppressed with their reason",
+			options: []Option{WithRules(rules...), WithParallelism(0), WithIgnore(&IgnoreSpec{
+				Rules:  []string{"CTAC004_SINGLE_PREMISE_RULE"},
+				Reason: []string{"Short arguments are fine here"},
+			})},
+			wantIssues:     []string{"CTAC003_MISSING_CONCLUSION_RULE"},
+			wantSuppressed: []string{"CTAC004_SINGLE_PREMISE_RULE"},
+			wantSeverity:   SeverityError,
+			wantReason:     "Short arguments are fine here",
+		},
+		{
+			name:         "Explicit overrides win over the config",
+			options:      []Option{WithRules(rules...), WithConfig(&Config{Severities: map[string]Severity{"CTAC003_MISSING_CONCLUSION_RULE": SeverityWarning}}), WithSeverityOverride("CTAC003_MISSING_CONCLUSION_RULE", SeverityInfo)},
+			wantIssues:   []string{"CTAC003_MISSING_CONCLUSION_RULE", "CTAC004_SINGLE_PREMISE_RULE"},
+			wantSeverity: SeverityInfo,
+		},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			report := NewAnalyzer(tc.options...).Analyze(context.Background(), argument)
+
+			if got := issueIDs(report.Issues); strings.Join(got, ",") != strings.Join(tc.wantIssues, ",") {
+				t.Fatalf("got issues %v but we wanted %v", got, tc.wantIssues)
+			}
+			var suppressed []string
+			for _, issue := range report.Suppressed {
+				suppressed = append(suppressed, issue.RuleID)
+				if issue.Reason != tc.wantReason {
+					t.Fatalf("got reason %q but we wanted %q", issue.Reason, tc.wantReason)
+				}
+			}
+			if strings.Join(suppressed, ",") != strings.Join(tc.wantSuppressed, ",") {
+				t.Fatalf("got suppressed %v but we wanted %v", suppressed, tc.wantSuppressed)
+			}
+			if got := report.Issues[0].Severity; got != tc.wantSeverity {
+				t.Fatalf("got severity %s but we wanted %s", got, tc.wantSeverity)
+			}
+			if len(report.RuleErrors) != 1 || report.RuleErrors[0].RuleID != "TEST_FAIL" {
+				t.Fatalf("got rule errors %v but we wanted one for TEST_FAIL", report.RuleErrors)
+			}
+			if len(report.Timings) != len(rules) {
+				t.Fatalf("got %d timings but we wanted %d", len(report.Timings), len(rules))
+			}
+			if report.Score <= 0 || report.Score >= 100 {
+				t.Fatalf("got score %d but we wanted one between 0 and 100", report.Score)
+			}
+			for _, issue := range report.Issues {
+				if issue.Argument == nil || issue.Argument.Title != argument.Title {
+					t.Fatalf("got argument %+v but we wanted the issue tagged with %q", issue.Argument, argument.Title)
+				}
+			}
+			if _, err := json.Marshal(report); err != nil {
+				t.Fatalf("encoding report: %v", err)
+			}
+		})
+	}
+}
+
+func issueIDs(issues []Issue) []string {
+	var ids []string
+	for _, issue := range issues {
+		ids = append(ids, issue.RuleID)
+	}
+	return ids
+}
+
+func TestAcceptedWithErrors(t *testing.T) {
+
+	argument := Argument{Title: "Accepted", Premises: []Premise{{Id: "P1", Text: "Queues decouple services"}}}
+	rules := []Rule{MissingConclusionRule{}, SinglePremiseRule{}, AcceptedWithErrorsRule{}}
+
+	cases := []struct {
+		name    string
+		status  Status
+		options []Option
+		want    bool
+	}{
+		{name: "Accepted with errors", status: StatusAccepted, want: true},
+		{name: "Proposed with errors", status: StatusProposed},
+		{name: "Ignored errors do not count", status: StatusAccepted, options: []Option{WithIgnore(&IgnoreSpec{Rules: []string{"CTAC003_MISSING_CONCLUSION_RULE"}})}},
+		{name: "Downgraded errors do not count", status: StatusAccepted, options: []Option{WithSeverityOverride("CTAC003_MISSING_CONCLUSION_RULE", SeverityWarning)}},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			argument := argument
+			argument.Status = tc.status
+			report := NewAnalyzer(append(tc.options, WithRules(rules...))...).Analyze(context.Background(), argument)
+
+			got := false
+			for _, issue := range report.Issues {
+				got = got || issue.RuleID == "CTAC011_ACCEPTED_WITH_ERRORS"
+			}
+			if got != tc.want {
+				t.Fatalf("got CTAC011 %v but we wanted %v: %v", got, tc.want, report.Issues)
+			}
+			if report.Metadata.Status != tc.status {
+				t.Fatalf("got status %q in the report but we wanted %q", report.Metadata.Status, tc.status)
+			}
+		})
+	}
+}
+
+type panickingIssueRule struct{ panickingRule }
+
+func (panickingIssueRule) Check(Argument) []Issue { return nil }
+
+func (panickingIssueRule) CheckIssues(Argument, []Issue) []Issue {
+	panic("boom")
+}
+
+func TestAnalyzerGuardsIssueRules(t *testing.T) {
+
+	argument := Argument{Title: "Issue rules", Premises: []Premise{{Id: "P1", Text: "Issue rules can panic"}}}
+	report := NewAnalyzer(WithRules(MissingConclusionRule{}, panickingIssueRule{})).Analyze(context.Background(), argument)
+
+	if len(report.RuleErrors) != 1 || report.RuleErrors[0].RuleID != "TEST_PANIC" || !strings.Contains(report.RuleErrors[0].Error(), "panic: boom") {
+		t.Fatalf("got rule errors %v but we wanted the panic of TEST_PANIC", report.RuleErrors)
+	}
+	if got := issueIDs(report.Issues); strings.Join(got, ",") != "CTAC003_MISSING_CONCLUSION_RULE" {
+		t.Fatalf("got issues %v but we wanted those of the other rules", got)
+	}
+}
@@ -0,0 +1,122 @@
+package ctac
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Assumption is a named assumption shared by arguments, which cite it by ID
+// on a premise or on the whole argument.
+type Assumption struct {
+	ID         string     `yaml:"id" json:"id"`
+	Text       string     `yaml:"text" json:"text"`
+	Owner      string     `yaml:"owner" json:"owner"`
+	Confidence Confidence `yaml:"confidence" json:"confidence"`
+	// ReviewDate is when the owner should check the assumption again.
+	ReviewDate Date `yaml:"reviewDate" json:"reviewDate"`
+}
+
+// AssumptionRegistry is the assumptions file, by default assumptions.yaml.
+type AssumptionRegistry struct {
+	Assumptions []Assumption `yaml:"assumptions" json:"assumptions"`
+
+	byID map[string]Assumption
+}
+
+var defaultAssumptionsFiles = []string{
+	"assumptions.yaml",
+	"assumptions.yml",
+	"ctac.assumptions.yaml",
+	"ctac.assumptions.yml",
+}
+
+// LoadAssumptions reads an assumptions file. An empty filePath looks for
+// the default names in baseDir and returns a nil registry when there is
+// none.
+func LoadAssumptions(filePath, baseDir string) (*AssumptionRegistry, error) {
+	if filePath == "" {
+		for _, name := range defaultAssumptionsFiles {
+			if _, err := os.Stat(filepath.Join(baseDir, name)); err == nil {
+				filePath = name
+				break
+			}
+		}
+		if filePath == "" {
+			return nil, nil
+		}
+	}
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(baseDir, filePath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	registry := AssumptionRegistry{}
+	if err := yaml.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("assumptions file %s: %w", filePath, err)
+	}
+	if err := registry.index(); err != nil {
+		return nil, fmt.Errorf("assumptions file %s: %w", filePath, err)
+	}
+	return &registry, nil
+}
+
+// index validates the registry and indexes it by ID.
+func (r *AssumptionRegistry) index() error {
+	r.byID = make(map[string]Assumption, len(r.Assumptions))
+	for i, assumption := range r.Assumptions {
+		if assumption.ID == "" {
+			return fmt.Errorf("assumptions[%d]: id is required", i)
+		}
+		if _, ok := r.byID[assumption.ID]; ok {
+			return fmt.Errorf("assumptions[%d]: duplicate id %q", i, assumption.ID)
+		}
+		if !assumption.Confidence.valid() {
+			return fmt.Errorf("assumptions[%d]: unknown confidence %q (want low, medium or high)", i, assumption.Confidence)
+		}
+		r.byID[assumption.ID] = assumption
+	}
+	return nil
+}
+
+// Get returns the assumption with the given ID.
+func (r *AssumptionRegistry) Get(id string) (Assumption, bool) {
+	if r == nil {
+		return Assumption{}, false
+	}
+	if r.byID == nil {
+		r.index()
+	}
+	assumption, ok := r.byID[id]
+	return assumption, ok
+}
+
+// Dependency is how an argument depends on an assumption: cited by the
+// argument itself, by some of its premises, or both.
+type Dependency struct {
+	Argument Argument
+	Direct   bool
+	Premises []string
+}
+
+// DependsOn reports whether argument cites the assumption, and where.
+func DependsOn(argument Argument, assumptionID string) (Dependency, bool) {
+	dependency := Dependency{Argument: argument}
+	for _, id := range argument.Assumptions {
+		dependency.Direct = dependency.Direct || id == assumptionID
+	}
+	for _, premise := range argument.Premises {
+		for _, id := range premise.Assumptions {
+			if id == assumptionID {
+				dependency.Premises = append(dependency.Premises, premise.Id)
+				break
+			}
+		}
+	}
+	return dependency, dependency.Direct || len(dependency.Premises) > 0
+}
@@ -0,0 +1,118 @@
+package ctac
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const assumptionsFile = `assumptions:
+-   id: GROWTH_20
+    text: "Traffic grows 20% a year"
+    owner: alice
+    confidence: medium
+    reviewDate: 2026-01-01
+-   id: TEAM_SIZE
+    text: "The platform team stays at 6 people"
+    owner: bob
+    confidence: high
+    reviewDate: 2027-01-01
+`
+
+func TestAssumptionRule(t *testing.T) {
+
+	dir := writeFiles(t, map[string]string{"assumptions.yaml": assumptionsFile})
+	registry, err := LoadAssumptions("", dir)
+	if err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+	today, _ := ParseDate("2026-06-01")
+
+	cases := []struct {
+		name         string
+		argument     Argument
+		wantMessages []string
+	}{
+		{
+			name:     "Assumptions under review are fine",
+			argument: Argument{Assumptions: []string{"TEAM_SIZE"}},
+		},
+		{
+			name:         "Unknown assumptions are errors",
+			argument:     Argument{Premises: []Premise{{Id: "P1", Assumptions: []string{"NOPE"}}}},
+			wantMessages: []string{"Premise P1 cites assumption NOPE, which is not in the assumptions registry"},
+		},
+		{
+			name:         "Overdue assumptions are warnings",
+			argument:     Argument{Assumptions: []string{"GROWTH_20"}},
+			wantMessages: []string{"This argument relies on assumption GROWTH_20 (owner: alice), which was due for review on 2026-01-01"},
+		},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			issues := AssumptionRule{Registry: registry, Today: today}.Check(tc.argument)
+			if len(issues) != len(tc.wantMessages) {
+				t.Fatalf("got %d issue%s but we wanted %d: %v", len(issues), plural(len(issues)), len(tc.wantMessages), issues)
+			}
+			for i, issue := range issues {
+				if issue.Message != tc.wantMessages[i] {
+					t.Fatalf("got %q but we wanted %q", issue.Message, tc.wantMessages[i])
+				}
+			}
+		})
+	}
+
+	if issues := (AssumptionRule{}).Check(Argument{Assumptions: []string{"NOPE"}}); len(issues) != 0 {
+		t.Fatalf("expected no issues without a registry, got %v", issues)
+	}
+}
+
+func TestAssumptionRegistryErrors(t *testing.T) {
+
+	dir := writeFiles(t, map[string]string{
+		"dup.yaml":  "assumptions:\n-   id: A\n-   id: A\n",
+		"date.yaml": "assumptions:\n-   id: A\n    reviewDate: next year\n",
+	})
+	for name, want := range map[string]string{"dup.yaml": `duplicate id "A"`, "date.yaml": "invalid date"} {
+		if _, err := LoadAssumptions(name, dir); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: got error %v but we wanted one containing %q", name, err, want)
+		}
+	}
+	if registry, err := LoadAssumptions("", dir); registry != nil || err != nil {
+		t.Fatalf("expected no registry without an assumptions file, got %v and %v", registry, err)
+	}
+}
+
+func TestImpact(t *testing.T) {
+
+	dir := writeFiles(t, map[string]string{
+		"assumptions.yaml":   assumptionsFile,
+		"facts.yaml":         "premises:\n-   id: TRAFFIC\n    text: \"Traffic will double by 2030\"\n    assumptions: [GROWTH_20]\n",
+		"decisions/a.yaml":   "title: Shard the database\nassumptions: [GROWTH_20]\npremises:\n-   id: P1\n    text: x\n",
+		"decisions/b.md":     "# B\n\n```ctac\ntitle: Buy bigger disks\npremises:\n-   id: P1\n    ref: ../facts.yaml#TRAFFIC\n```\n",
+		"decisions/c.yaml":   "title: Hire\npremises:\n-   id: P1\n    assumptions: [TEAM_SIZE]\n",
+		"README.md":          "# Decisions\n",
+		".git/ignored.yaml":  "title: [",
+		"decisions/bad.yaml": "title: [",
+	})
+
+	files, errs := LoadDir(dir)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "bad.yaml") {
+		t.Fatalf("got errors %v but we wanted one for bad.yaml", errs)
+	}
+
+	var impacted []string
+	for _, file := range files {
+		if dependency, ok := DependsOn(file.Argument, "GROWTH_20"); ok {
+			rel, _ := filepath.Rel(dir, file.Path)
+			impacted = append(impacted, rel+":"+strings.Join(dependency.Premises, ","))
+		}
+	}
+	sort.Strings(impacted)
+	want := []string{"decisions/a.yaml:", "decisions/b.md:P1", "facts.yaml:TRAFFIC"}
+	if strings.Join(impacted, " ") != strings.Join(want, " ") {
+		t.Fatalf("got %v but we wanted %v", impacted, want)
+	}
+}
@@ -0,0 +1,252 @@
+package ctac
+
+import (
+	"context"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Config struct {
+	DuplicatePremises DuplicatePremisesConfig `yaml:"duplicatePremises" json:"duplicatePremises"`
+	Quotes            QuotesConfig            `yaml:"quotes" json:"quotes"`
+	Freshness         FreshnessConfig         `yaml:"freshness" json:"freshness"`
+	Score             ScoreConfig             `yaml:"score" json:"score"`
+	Lexicons          LexiconChanges          `yaml:"lexicons" json:"lexicons,omitempty"`
+	// LexiconFiles are read relative to the config file and applied after Lexicons.
+	LexiconFiles []string         `yaml:"lexiconFiles" json:"lexiconFiles,omitempty"`
+	CustomRules  []CustomRuleSpec `yaml:"customRules" json:"customRules,omitempty"`
+	// Plugins are read relative to the config file.
+	Plugins      []PluginSpec      `yaml:"plugins" json:"plugins,omitempty"`
+	ProcessRules []ProcessRuleSpec `yaml:"processRules" json:"processRules,omitempty"`
+	// Severities overrides the severity of the issues of a rule.
+	Severities map[string]Severity `yaml:"severities" json:"severities,omitempty"`
+	// ADR maps the sections of Markdown ADRs for `ctac import adr`.
+	ADR ADRMapping `yaml:"adr" json:"adr"`
+	// AssumptionsFile is the assumptions registry, relative to the config
+	// file. By default assumptions.yaml is used when it exists.
+	AssumptionsFile string `yaml:"assumptionsFile" json:"assumptionsFile,omitempty"`
+
+	lexicons    *Lexicons
+	assumptions *AssumptionRegistry
+	// extraRules are the compiled custom rules, plugins and process rules,
+	// run after the built-in rules.
+	extraRules []Rule
+}
+
+type DuplicatePremisesConfig struct {
+	// Threshold is the similarity (0-1) above which two premises are reported as duplicates.
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+}
+
+type FreshnessConfig struct {
+	// MaxAgeDays is how old evidence can be before it is reported as stale (default 365).
+	MaxAgeDays int `yaml:"maxAgeDays" json:"maxAgeDays"`
+}
+
+// QuoteMode controls how lexicon rules treat words inside quotation marks
+// or in the quote of a cited source.
+type QuoteMode string
+
+const (
+	QuoteModeDowngrade QuoteMode = "downgrade" // report quoted words one severity lower (default)
+	QuoteModeSkip      QuoteMode = "skip"      // do not report quoted words
+	QuoteModeInclude   QuoteMode = "include"   // report quoted words like the author's own
+)
+
+type QuotesConfig struct {
+	Mode QuoteMode `yaml:"mode" json:"mode"`
+}
+
+var defaultConfigFiles = []string{
+	"ctac.config.yaml",
+	"ctacconfig.yaml",
+	"ctacConfig.yaml",
+	"ctac.config.yml",
+	"ctacconfig.yml",
+	"ctacConfig.yml",
+}
+
+func resolveConfigPath(userPath string) string {
+	if userPath != "" {
+		return userPath
+	}
+
+	for _, defaultConfigFilePath := range defaultConfigFiles {
+		if _, err := os.Stat(defaultConfigFilePath); err == nil {
+			return defaultConfigFilePath
+		}
+	}
+	return ""
+}
+
+// LoadConfig reads the config file at filePath, or the first default config
+// file in the working directory when filePath is empty. Plugins and process
+// rules run code, so a config file found in the working directory may only
+// declare them when loaded with LoadTrustedConfig.
+func LoadConfig(filePath string) (*Config, error) {
+	return loadConfig(filePath, filePath != "", true)
+}
+
+// LoadTrustedConfig is LoadConfig, but trusts a config file found in the
+// working directory to declare plugins and process rules.
+func LoadTrustedConfig(filePath string) (*Config, error) {
+	return loadConfig(filePath, true, true)
+}
+
+// LoadConfigSettings is LoadConfig for commands that do not run rules, such
+// as listing the arguments that depend on an assumption. It leaves out the
+// plugins and process rules, so it runs no code and needs no trust.
+func LoadConfigSettings(filePath string) (*Config, error) {
+	return loadConfig(filePath, true, false)
+}
+
+func loadConfig(filePath string, trusted, external bool) (*Config, error) {
+	configFilePath := resolveConfigPath(filePath)
+	config := Config{}
+	if configFilePath == "" {
+		if err := config.Compile("."); err != nil {
+			return nil, err
+		}
+		return &config, nil
+	}
+	if _, err := os.Stat(configFilePath); err != nil {
+		return nil, fmt.Errorf("no such file or directory for config file at %s. Please provide a valid path to your config file", configFilePath)
+	}
+	data, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, err
+	}
+	if !external {
+		config.Plugins, config.ProcessRules = nil, nil
+	}
+	if !trusted && (len(config.Plugins) > 0 || len(config.ProcessRules) > 0) {
+		return nil, fmt.Errorf("config file %s was found in the working directory and declares plugins or processRules, which run code. Pass it with -configFile or -trustConfig to run them", configFilePath)
+	}
+	if err := config.Compile(filepath.Dir(configFilePath)); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", configFilePath, err)
+	}
+	return &config, err
+}
+
+// Compile validates the config and compiles its lexicons and custom rules,
+// reading lexicon files and the assumptions registry relative to baseDir.
+// LoadConfig calls it; configs built in code must call it before use.
+func (c *Config) Compile(baseDir string) error {
+	if t := c.DuplicatePremises.Threshold; t < 0 || t > 1 {
+		return fmt.Errorf("duplicatePremises.threshold must be between 0 and 1, got %v", t)
+	}
+	if c.Freshness.MaxAgeDays < 0 {
+		return fmt.Errorf("freshness.maxAgeDays must not be negative, got %d", c.Freshness.MaxAgeDays)
+	}
+	if err := c.Score.Weights.validate(); err != nil {
+		return err
+	}
+	switch c.Quotes.Mode {
+	case "", QuoteModeDowngrade, QuoteModeSkip, QuoteModeInclude:
+	default:
+		return fmt.Errorf("quotes.mode must be one of %q, %q or %q, got %q", QuoteModeDowngrade, QuoteModeSkip, QuoteModeInclude, c.Quotes.Mode)
+	}
+
+	for ruleID, severity := range c.Severities {
+		switch severity {
+		case SeverityInfo, SeverityWarning, SeverityError:
+		default:
+			return fmt.Errorf("severities.%s: unknown severity %q", ruleID, severity)
+		}
+	}
+
+	lexicons, err := loadLexicons(c, baseDir)
+	if err != nil {
+		return err
+	}
+	assumptions, err := LoadAssumptions(c.AssumptionsFile, baseDir)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	extraRules := make([]Rule, 0, len(c.CustomRules)+len(c.Plugins)+len(c.ProcessRules))
+	for i, spec := range c.CustomRules {
+		if err := checkExternalRuleID(spec.ID, used); err != nil {
+			return fmt.Errorf("customRules[%d]: %w", i, err)
+		}
+		rule, err := CompileCustomRule(spec)
+		if err != nil {
+			return fmt.Errorf("customRules[%d]: %w", i, err)
+		}
+		extraRules = append(extraRules, rule)
+	}
+	for i, spec := range c.Plugins {
+		if err := checkExternalRuleID(spec.ID, used); err != nil {
+			closeRules(extraRules)
+			return fmt.Errorf("plugins[%d]: %w", i, err)
+		}
+		rule, err := LoadWasmRule(context.Background(), spec, baseDir)
+		if err != nil {
+			closeRules(extraRules)
+			return fmt.Errorf("plugins[%d]: %w", i, err)
+		}
+		extraRules = append(extraRules, rule)
+	}
+	for i, spec := range c.ProcessRules {
+		if err := checkExternalRuleID(spec.ID, used); err != nil {
+			closeRules(extraRules)
+			return fmt.Errorf("processRules[%d]: %w", i, err)
+		}
+		rule, err := NewProcessRule(spec, baseDir)
+		if err != nil {
+			closeRules(extraRules)
+			return fmt.Errorf("processRules[%d]: %w", i, err)
+		}
+		extraRules = append(extraRules, rule)
+	}
+
+	c.lexicons = lexicons
+	c.assumptions = assumptions
+	c.extraRules = extraRules
+	return nil
+}
+
+// Assumptions returns the assumptions registry, or nil when there is none.
+func (c *Config) Assumptions() *AssumptionRegistry {
+	return c.assumptions
+}
+
+// Close releases the resources held by plugins.
+func (c *Config) Close() {
+	closeRules(c.extraRules)
+	c.extraRules = nil
+}
+
+func closeRules(rules []Rule) {
+	for _, rule := range rules {
+		if plugin, ok := rule.(*WasmRule); ok {
+			plugin.Close(context.Background())
+		}
+	}
+}
+
+// checkExternalRuleID validates the ID of a rule defined outside ctac and
+// records it in used. The CTAC prefix is reserved for built-in rules.
+func checkExternalRuleID(id string, used map[string]bool) error {
+	if id == "" {
+		return fmt.Errorf("rule id is required")
+	}
+	if strings.HasPrefix(strings.ToUpper(id), "CTAC") {
+		return fmt.Errorf("rule id %q uses the CTAC prefix reserved for built-in rules", id)
+	}
+	if used[id] {
+		return fmt.Errorf("rule id %q is already used", id)
+	}
+	if used != nil {
+		used[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package ctac
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Targets a custom rule can match against.
+const (
+	TargetPremises   = "premises"
+	TargetConclusion = "conclusion"
+	TargetTitle      = "title"
+)
+
+const (
+	defaultCustomMessage = `{{if .ID}}{{.ID}}{{else}}The {{.Target}}{{end}}{{if .Text}} {{printf "%q" .Text}}{{end}} matches custom rule {{.RuleID}}{{if .Matches}}: {{.Matches}}{{end}}`
+	defaultCustomHint    = "Review the argument against your team's guidelines"
+)
+
+// CustomRuleSpec declares a team-specific rule in the config file.
+//
+// A rule with patterns reports every target whose text matches one of
+// them; a rule without patterns reports every target that meets the
+// conditions, or the argument as a whole when it has no targets. Message
+// and Hint are Go templates over customRuleData.
+type CustomRuleSpec struct {
+	ID       string               `yaml:"id" json:"id"`
+	Severity Severity             `yaml:"severity" json:"severity"`
+	Targets  []string             `yaml:"targets" json:"targets,omitempty"`
+	Patterns []LexiconEntry       `yaml:"patterns" json:"patterns,omitempty"`
+	When     CustomRuleConditions `yaml:"when" json:"when"`
+	Message  string               `yaml:"message" json:"message,omitempty"`
+	Hint     string               `yaml:"hint" json:"hint,omitempty"`
+}
+
+// CustomRuleConditions restrict when a custom rule applies. Confidence is
+// checked against the matched premise or conclusion; the other conditions
+// apply to the whole argument. Expr is a boolean expression over the
+// argument (see CompileExpression).
+type CustomRuleConditions struct {
+	Expr        string       `yaml:"expr" json:"expr,omitempty"`
+	Confidence  []Confidence `yaml:"confidence" json:"confidence,omitempty"`
+	Modality    []Modality   `yaml:"modality" json:"modality,omitempty"`
+	MinPremises *int         `yaml:"minPremises" json:"minPremises,omitempty"`
+	MaxPremises *int         `yaml:"maxPremises" json:"maxPremises,omitempty"`
+}
+
+// customRuleData is what message and hint templates can refer to.
+type customRuleData struct {
+	RuleID     string
+	Target     string
+	ID         string
+	Text       string
+	Matches    string
+	Confidence Confidence
+	Modality   Modality
+	Premises   int
+	Title      string
+}
+
+// CustomRule is a rule compiled from a CustomRuleSpec.
+type CustomRule struct {
+	spec     CustomRuleSpec
+	expr     *Expression
+	phrases  []lexiconPhrase
+	message  *template.Template
+	hint     *template.Template
+	severity Severity
+}
+
+func CompileCustomRule(spec CustomRuleSpec) (*CustomRule, error) {
+	if spec.ID == "" {
+		return nil, fmt.Errorf("custom rule needs an id")
+	}
+
+	severity := spec.Severity
+	switch severity {
+	case "":
+		severity = SeverityWarning
+	case SeverityInfo, SeverityWarning, SeverityError:
+	default:
+		return nil, fmt.Errorf("rule %s: unknown severity %q", spec.ID, spec.Severity)
+	}
+
+	for _, target := range spec.Targets {
+		switch target {
+		case TargetPremises, TargetConclusion, TargetTitle:
+		default:
+			return nil, fmt.Errorf("rule %s: unknown target %q, expected %s, %s or %s", spec.ID, target, TargetPremises, TargetConclusion, TargetTitle)
+		}
+	}
+	if len(spec.Patterns) > 0 && len(spec.Targets) == 0 {
+		return nil, fmt.Errorf("rule %s: patterns need at least one target", spec.ID)
+	}
+
+	for _, c := range spec.When.Confidence {
+		if c != Low && c != Medium && c != High {
+			return nil, fmt.Errorf("rule %s: unknown confidence %q", spec.ID, c)
+		}
+	}
+	for _, m := range spec.When.Modality {
+		if m != ModalityMust && m != ModalityShould && m != ModalityCould {
+			return nil, fmt.Errorf("rule %s: unknown modality %q", spec.ID, m)
+		}
+	}
+
+	var expr *Expression
+	if spec.When.Expr != "" {
+		var err error
+		if expr, err = CompileExpression(spec.When.Expr); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", spec.ID, err)
+		}
+	}
+
+	phrases, err := buildPhrases(spec.Patterns)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", spec.ID, err)
+	}
+
+	messageText, hintText := spec.Message, spec.Hint
+	if messageText == "" {
+		messageText = defaultCustomMessage
+	}
+	if hintText == "" {
+		hintText = defaultCustomHint
+	}
+	message, err := template.New(spec.ID + " message").Option("missingkey=error").Parse(messageText)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: message: %w", spec.ID, err)
+	}
+	hint, err := template.New(spec.ID + " hint").Option("missingkey=error").Parse(hintText)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: hint: %w", spec.ID, err)
+	}
+
+	rule := &CustomRule{spec: spec, expr: expr, phrases: phrases, message: message, hint: hint, severity: severity}
+
+	// Execute the templates once so unknown fields are reported at load time.
+	if _, err := rule.render(rule.message, customRuleData{}); err != nil {
+		return nil, fmt.Errorf("rule %s: message: %w", spec.ID, err)
+	}
+	if _, err := rule.render(rule.hint, customRuleData{}); err != nil {
+		return nil, fmt.Errorf("rule %s: hint: %w", spec.ID, err)
+	}
+	return rule, nil
+}
+
+func (rule *CustomRule) ID() string {
+	return rule.spec.ID
+}
+
+func (rule *CustomRule) render(tmpl *template.Template, data customRuleData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func containsValue[T comparable](values []T, v T) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// argumentMatches checks the argument-level conditions.
+func (rule *CustomRule) argumentMatches(argument Argument) bool {
+	when := rule.spec.When
+	if len(when.Modality) > 0 && !containsValue(when.Modality, argument.Conclusion.Modality) {
+		return false
+	}
+	if when.MinPremises != nil && len(argument.Premises) < *when.MinPremises {
+		return false
+	}
+	if when.MaxPremises != nil && len(argument.Premises) > *when.MaxPremises {
+		return false
+	}
+	if rule.expr != nil && !rule.expr.Eval(argument) {
+		return false
+	}
+	return true
+}
+
+// matchTarget returns whether a target meets the rule, and the phrases it matched.
+func (rule *CustomRule) matchTarget(text string, confidence Confidence, hasConfidence bool) (bool, []string) {
+	if hasConfidence && len(rule.spec.When.Confidence) > 0 && !containsValue(rule.spec.When.Confidence, confidence) {
+		return false, nil
+	}
+	if len(rule.phrases) == 0 {
+		return true, nil
+	}
+	var matched []string
+	for _, phrase := range rule.phrases {
+		locs := phrase.locate(text)
+		if len(locs) == 0 {
+			continue
+		}
+		// Report what a regex matched rather than the regex itself.
+		if phrase.wholeWord {
+			matched = append(matched, phrase.Phrase)
+		} else {
+			matched = append(matched, text[locs[0][0]:locs[0][1]])
+		}
+	}
+	return len(matched) > 0, matched
+}
+
+func (rule *CustomRule) issue(data customRuleData) Issue {
+	message, err := rule.render(rule.message, data)
+	if err != nil {
+		message = fmt.Sprintf("custom rule %s: %v", rule.spec.ID, err)
+	}
+	hint, err := rule.render(rule.hint, data)
+	if err != nil {
+		hint = ""
+	}
+	return Issue{
+		RuleID:   rule.spec.ID,
+		Severity: rule.severity,
+		Message:  message,
+		Hint:     hint,
+	}
+}
+
+func (rule *CustomRule) Check(argument Argument) []Issue {
+
+	if !rule.argumentMatches(argument) {
+		return nil
+	}
+
+	base := customRuleData{
+		RuleID:   rule.spec.ID,
+		Modality: argument.Conclusion.Modality,
+		Premises: len(argument.Premises),
+		Title:    argument.Title,
+	}
+
+	if len(rule.spec.Targets) == 0 {
+		data := base
+		data.Target = "argument"
+		data.Confidence = argument.Conclusion.Confidence
+		return []Issue{rule.issue(data)}
+	}
+
+	var issues []Issue
+	for _, target := range rule.spec.Targets {
+		switch target {
+		case TargetPremises:
+			for _, p := range argument.Premises {
+				if ok, matched := rule.matchTarget(p.Text, p.Confidence, true); ok {
+					data := base
+					data.Target, data.ID, data.Text, data.Confidence = "premise", p.Id, p.Text, p.Confidence
+					data.Matches = strings.Join(matched, ", ")
+					issue := rule.issue(data)
+					issue.Line, issue.Premise, issue.Words = p.Line, p.Id, matched
+					issues = append(issues, issue)
+				}
+			}
+		case TargetConclusion:
+			c := argument.Conclusion
+			if ok, matched := rule.matchTarget(c.Text, c.Confidence, true); ok {
+				data := base
+				data.Target, data.Text, data.Confidence = "conclusion", c.Text, c.Confidence
+				data.Matches = strings.Join(matched, ", ")
+				issue := rule.issue(data)
+				issue.Line, issue.Words = c.Line, matched
+				issues = append(issues, issue)
+			}
+		case TargetTitle:
+			if ok, matched := rule.matchTarget(argument.Title, "", false); ok {
+				data := base
+				data.Target, data.Text = "title", argument.Title
+				data.Matches = strings.Join(matched, ", ")
+				issues = append(issues, rule.issue(data))
+			}
+		}
+	}
+	return issues
+}
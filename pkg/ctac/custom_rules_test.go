@@ -0,0 +1,144 @@
+package ctac
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const customRulesConfig = `
+customRules:
+  - id: TEAM001_BUZZWORDS
+    severity: warning
+    targets: [premises, conclusion]
+    patterns:
+      - synergy
+      - regex: '\bleverag(e|ing)\b'
+    message: "{{.Target}} {{.ID}} uses buzzwords: {{.Matches}}"
+    hint: "Say what {{.Matches}} means in practice"
+  - id: TEAM002_MUST_NEEDS_THREE_PREMISES
+    severity: error
+    when:
+      modality: [must]
+      maxPremises: 2
+    message: "A 'must' conclusion is backed by only {{.Premises}} premises"
+  - id: TEAM003_LOW_CONFIDENCE_PREMISE
+    severity: info
+    targets: [premises]
+    when:
+      confidence: [low]
+`
+
+func TestCustomRules(t *testing.T) {
+
+	config := Config{}
+	if err := yaml.Unmarshal([]byte(customRulesConfig), &config); err != nil {
+		t.Fatalf("parsing config: %v", err)
+	}
+	if err := config.Compile("."); err != nil {
+		t.Fatalf("compiling config: %v", err)
+	}
+
+	argument := Argument{
+		Title: "Platform consolidation",
+		Premises: []Premise{
+			{Id: "P1", Text: "Leveraging one platform creates synergy between teams", Confidence: High},
+			{Id: "P2", Text: "Two of our 5 platforms are unmaintained", Confidence: Low},
+		},
+		Conclusion: Conclusion{Text: "We must consolidate the platforms", Modality: ModalityMust, Confidence: High},
+	}
+
+	want := map[string]int{
+		"TEAM001_BUZZWORDS":                 1,
+		"TEAM002_MUST_NEEDS_THREE_PREMISES": 1,
+		"TEAM003_LOW_CONFIDENCE_PREMISE":    1,
+	}
+
+	runners := map[string]func() []Issue{
+		"sequential": func() []Issue { return RunRulesSequential(argument, ConfiguredRules(&config)) },
+		"parallel":   func() []Issue { return RunRulesParallel(argument, ConfiguredRules(&config), 2) },
+	}
+	for name, run := range runners {
+		got := make(map[string]int)
+		for _, issue := range run() {
+			if strings.HasPrefix(issue.RuleID, "TEAM") {
+				got[issue.RuleID]++
+			}
+			if issue.RuleID == "TEAM001_BUZZWORDS" && issue.Message != "premise P1 uses buzzwords: synergy, Leveraging" {
+				t.Fatalf("%s: unexpected message %q", name, issue.Message)
+			}
+		}
+		for id, n := range want {
+			if got[id] != n {
+				t.Fatalf("%s: got %d issue%s for %s but we wanted %d", name, got[id], plural(got[id]), id, n)
+			}
+		}
+	}
+}
+
+func TestCustomRuleErrors(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		spec    CustomRuleSpec
+		wantErr string
+	}{
+		{name: "Missing id", spec: CustomRuleSpec{}, wantErr: "needs an id"},
+		{name: "Unknown severity", spec: CustomRuleSpec{ID: "X", Severity: "fatal"}, wantErr: "unknown severity"},
+		{name: "Unknown target", spec: CustomRuleSpec{ID: "X", Targets: []string{"sources"}}, wantErr: "unknown target"},
+		{name: "Patterns without target", spec: CustomRuleSpec{ID: "X", Patterns: []LexiconEntry{{Term: "a"}}}, wantErr: "need at least one target"},
+		{name: "Invalid regex", spec: CustomRuleSpec{ID: "X", Targets: []string{TargetTitle}, Patterns: []LexiconEntry{{Regex: "("}}}, wantErr: "missing closing"},
+		{name: "Unknown template field", spec: CustomRuleSpec{ID: "X", Message: "{{.Author}}"}, wantErr: "Author"},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CompileCustomRule(tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	config := Config{CustomRules: []CustomRuleSpec{{ID: "CTAC001_MISSING_PREMISES"}}}
+	if err := config.Compile("."); err == nil {
+		t.Fatalf("expected an error for a custom rule reusing a built-in id")
+	}
+}
+
+func TestCustomRuleExpression(t *testing.T) {
+
+	rule, err := CompileCustomRule(CustomRuleSpec{
+		ID:       "TEAM004_WEAK_MUST",
+		Severity: SeverityError,
+		When:     CustomRuleConditions{Expr: "count(premises where confidence == 'low') > len(premises)/2 && conclusion.modality == 'must'"},
+		Message:  "Most premises are low confidence but the conclusion says must",
+	})
+	if err != nil {
+		t.Fatalf("compiling rule: %v", err)
+	}
+
+	weak := Argument{
+		Premises:   []Premise{{Confidence: Low}, {Confidence: Low}, {Confidence: High}},
+		Conclusion: Conclusion{Modality: ModalityMust},
+	}
+	strong := Argument{
+		Premises:   []Premise{{Confidence: High}, {Confidence: Low}},
+		Conclusion: Conclusion{Modality: ModalityMust},
+	}
+	if got := len(rule.Check(weak)); got != 1 {
+		t.Fatalf("weak argument: got %d issue%s but we wanted 1", got, plural(got))
+	}
+	if got := len(rule.Check(strong)); got != 0 {
+		t.Fatalf("strong argument: got %d issue%s but we wanted 0", got, plural(got))
+	}
+
+	_, err = CompileCustomRule(CustomRuleSpec{ID: "TEAM005", When: CustomRuleConditions{Expr: "count(premises) == 'many'"}})
+	if err == nil || !strings.Contains(err.Error(), "TEAM005") || !strings.Contains(err.Error(), "column") {
+		t.Fatalf("got error %v but we wanted a type error naming the rule and column", err)
+	}
+}
@@ -0,0 +1,81 @@
+package ctac
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DateLayout is how dates are written in argument and registry files.
+const DateLayout = "2006-01-02"
+
+// Date is a calendar day, written as 2006-01-02 in every format.
+type Date struct {
+	time.Time
+}
+
+// ParseDate parses a 2006-01-02 date.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+	}
+	return Date{t}, nil
+}
+
+// DateOf returns the day of t.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		// TOML dates and datetimes arrive as RFC 3339 timestamps.
+		t, rfcErr := time.Parse(time.RFC3339, string(text))
+		if rfcErr != nil {
+			return err
+		}
+		parsed = DateOf(t)
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid date %s (want \"YYYY-MM-DD\")", data)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+func (d Date) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+func (d *Date) UnmarshalYAML(node *yaml.Node) error {
+	return d.UnmarshalText([]byte(node.Value))
+}
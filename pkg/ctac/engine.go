@@ -0,0 +1,197 @@
+package ctac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ContextRule is implemented by rules that can fail or be cancelled, such as
+// plugins and process rules. The engine calls CheckContext instead of Check.
+type ContextRule interface {
+	Rule
+	CheckContext(ctx context.Context, a Argument) ([]Issue, error)
+}
+
+// Engine runs rules against an argument. Rules run one after the other
+// unless MaxWorkers is above 1. A rule that panics, fails or runs past
+// RuleTimeout yields a RuleError instead of stopping the other rules.
+type Engine struct {
+	Rules       []Rule
+	MaxWorkers  int
+	RuleTimeout time.Duration
+
+	// skipIssueRules leaves IssueRules to the caller. The Analyzer runs
+	// them itself, on the issues left after ignores and severity overrides.
+	skipIssueRules bool
+}
+
+// RuleResult is the outcome of running one rule.
+type RuleResult struct {
+	RuleID   string
+	Issues   []Issue
+	Err      *RuleError
+	Duration time.Duration
+}
+
+// Run runs the rules and returns their issues, in rule order, and the
+// errors of the rules that could not complete.
+func (e Engine) Run(ctx context.Context, a Argument) ([]Issue, []*RuleError) {
+	var issues []Issue
+	var ruleErrors []*RuleError
+	for _, result := range e.RunRules(ctx, a) {
+		issues = append(issues, result.Issues...)
+		if result.Err != nil {
+			ruleErrors = append(ruleErrors, result.Err)
+		}
+	}
+	return issues, ruleErrors
+}
+
+// RunRules runs the rules and returns one result per rule, in rule order.
+// Rules not started before ctx is done fail with the context's error.
+// IssueRules run last, one after the other, on the issues of the other
+// rules.
+func (e Engine) RunRules(ctx context.Context, a Argument) []RuleResult {
+	results := e.runAll(ctx, a)
+	if e.skipIssueRules {
+		return results
+	}
+
+	var found []Issue
+	for _, result := range results {
+		found = append(found, result.Issues...)
+	}
+	for i, rule := range e.Rules {
+		if issueRule, ok := rule.(IssueRule); ok {
+			result := e.runRule(ctx, issueCheck{IssueRule: issueRule, issues: found}, a)
+			results[i].Issues = append(results[i].Issues, result.Issues...)
+			results[i].Duration += result.Duration
+			if result.Err != nil {
+				results[i].Err = result.Err
+			}
+		}
+	}
+	return results
+}
+
+// issueCheck runs an IssueRule on the issues found by the other rules, so
+// the engine can guard it like any other rule.
+type issueCheck struct {
+	IssueRule
+	issues []Issue
+}
+
+func (c issueCheck) Check(a Argument) []Issue {
+	return c.CheckIssues(a, c.issues)
+}
+
+// runAll runs every rule's Check, on up to MaxWorkers goroutines.
+func (e Engine) runAll(ctx context.Context, a Argument) []RuleResult {
+	results := make([]RuleResult, len(e.Rules))
+	maxWorkers := e.MaxWorkers
+	if maxWorkers > len(e.Rules) {
+		maxWorkers = len(e.Rules)
+	}
+
+	if maxWorkers <= 1 {
+		for i, rule := range e.Rules {
+			results[i] = e.runRule(ctx, rule, a)
+		}
+		return results
+	}
+
+	jobs := make(chan int, len(e.Rules))
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(maxWorkers)
+
+	// start workers with a wait group
+	for w := 0; w < maxWorkers; w++ {
+		go func() {
+			defer waitGroup.Done()
+			for idx := range jobs {
+				results[idx] = e.runRule(ctx, e.Rules[idx], a)
+			}
+		}()
+	}
+
+	for i := range e.Rules {
+		jobs <- i
+	}
+	close(jobs)
+	waitGroup.Wait()
+
+	return results
+}
+
+// runRule runs a single rule under its deadline. The rule runs in its own
+// goroutine so a rule that ignores its context cannot hold up the engine;
+// such a goroutine is abandoned when the deadline passes.
+func (e Engine) runRule(ctx context.Context, rule Rule, a Argument) RuleResult {
+	start := time.Now()
+	result := RuleResult{RuleID: rule.ID()}
+
+	if err := ctx.Err(); err != nil {
+		result.Err = &RuleError{RuleID: result.RuleID, Err: err}
+		return result
+	}
+
+	if e.RuleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.RuleTimeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		issues []Issue
+		err    error
+	}
+	done := make(chan outcome, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		if contextRule, ok := rule.(ContextRule); ok {
+			issues, err := contextRule.CheckContext(ctx, a)
+			done <- outcome{issues: issues, err: err}
+			return
+		}
+		done <- outcome{issues: rule.Check(a)}
+	}()
+
+	select {
+	case out := <-done:
+		result.Issues = out.issues
+		if out.err != nil {
+			result.Issues = nil
+			result.Err = &RuleError{RuleID: result.RuleID, Err: out.err}
+		}
+	case <-ctx.Done():
+		err := ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) && e.RuleTimeout > 0 {
+			err = fmt.Errorf("timed out after %s", e.RuleTimeout)
+		}
+		result.Err = &RuleError{RuleID: result.RuleID, Err: err}
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// issuesWithErrors flattens results into issues, reporting rule errors as
+// issues of the failed rule.
+func issuesWithErrors(results []RuleResult) []Issue {
+	var issues []Issue
+	for _, result := range results {
+		issues = append(issues, result.Issues...)
+		if result.Err != nil {
+			issues = append(issues, ruleErrorIssue(result.Err))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,119 @@
+package ctac
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type panickingRule struct{}
+
+func (panickingRule) ID() string { return "TEST_PANIC" }
+func (panickingRule) Check(Argument) []Issue {
+	panic("boom")
+}
+
+type slowRule struct{ delay time.Duration }
+
+func (slowRule) ID() string { return "TEST_SLOW" }
+func (r slowRule) Check(Argument) []Issue {
+	time.Sleep(r.delay)
+	return []Issue{{RuleID: "TEST_SLOW", Severity: SeverityInfo, Message: "done"}}
+}
+
+type failingRule struct{}
+
+func (failingRule) ID() string             { return "TEST_FAIL" }
+func (failingRule) Check(Argument) []Issue { return nil }
+func (failingRule) CheckContext(context.Context, Argument) ([]Issue, error) {
+	return nil, errors.New("backend unavailable")
+}
+
+func TestEngine(t *testing.T) {
+
+	argument := Argument{Title: "Engine", Premises: []Premise{{Id: "P1", Text: "Rules can fail"}}}
+	rules := []Rule{MissingConclusionRule{}, panickingRule{}, slowRule{delay: time.Second}, failingRule{}, SinglePremiseRule{}}
+
+	for _, workers := range []int{1, 3} {
+		engine := Engine{Rules: rules, MaxWorkers: workers, RuleTimeout: 50 * time.Millisecond}
+		results := engine.RunRules(context.Background(), argument)
+
+		if len(results) != len(rules) {
+			t.Fatalf("workers=%d: got %d results but we wanted %d", workers, len(results), len(rules))
+		}
+		for i, result := range results {
+			if result.RuleID != rules[i].ID() {
+				t.Fatalf("workers=%d: result %d is for %s but we wanted %s", workers, i, result.RuleID, rules[i].ID())
+			}
+		}
+
+		wantErrs := map[string]string{"TEST_PANIC": "panic: boom", "TEST_SLOW": "timed out", "TEST_FAIL": "backend unavailable"}
+		for _, result := range results {
+			want, shouldFail := wantErrs[result.RuleID]
+			if !shouldFail {
+				if result.Err != nil || len(result.Issues) != 1 {
+					t.Fatalf("workers=%d: %s got %d issues and error %v but we wanted 1 issue", workers, result.RuleID, len(result.Issues), result.Err)
+				}
+				continue
+			}
+			if result.Err == nil || !strings.Contains(result.Err.Error(), want) {
+				t.Fatalf("workers=%d: %s got error %v but we wanted one containing %q", workers, result.RuleID, result.Err, want)
+			}
+		}
+
+		issues, ruleErrors := engine.Run(context.Background(), argument)
+		if len(issues) != 2 || len(ruleErrors) != 3 {
+			t.Fatalf("workers=%d: got %d issues and %d errors but we wanted 2 and 3", workers, len(issues), len(ruleErrors))
+		}
+	}
+}
+
+func TestEngineCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ruleErrors := Engine{Rules: BuiltinRules(nil)}.Run(ctx, Argument{})
+	if len(ruleErrors) != len(BuiltinRules(nil)) {
+		t.Fatalf("got %d errors but we wanted every rule to be cancelled", len(ruleErrors))
+	}
+	if !errors.Is(ruleErrors[0], context.Canceled) {
+		t.Fatalf("got error %v but we wanted context.Canceled", ruleErrors[0])
+	}
+}
+
+func TestRunRulesWrappersReportRuleErrors(t *testing.T) {
+
+	rules := []Rule{panickingRule{}, MissingPremiseRule{}}
+	for name, issues := range map[string][]Issue{
+		"sequential": RunRulesSequential(Argument{}, rules),
+		"parallel":   RunRulesParallel(Argument{}, rules, 2),
+	} {
+		if len(issues) != 2 || issues[0].RuleID != "TEST_PANIC" || issues[0].Severity != SeverityError {
+			t.Fatalf("%s: got %v but we wanted the panic reported as the first issue", name, issues)
+		}
+	}
+}
+
+func TestRunRulesWrappersRunIssueRules(t *testing.T) {
+
+	argument := Argument{Title: "Accepted", Metadata: Metadata{Status: StatusAccepted}}
+	rules := []Rule{AcceptedWithErrorsRule{}, MissingPremiseRule{}}
+	for name, issues := range map[string][]Issue{
+		"sequential":     RunRulesSequential(argument, rules),
+		"parallel":       RunRulesParallel(argument, rules, 2),
+		"all sequential": RunAllRulesSequential(argument),
+	} {
+		if got := issueIDs(issues); !slices.Contains(got, "CTAC011_ACCEPTED_WITH_ERRORS") {
+			t.Fatalf("%s: got %v but we wanted CTAC011 for an accepted argument with errors", name, got)
+		}
+	}
+
+	issues, _ := Engine{Rules: rules}.Run(context.Background(), Argument{Title: "Proposed", Metadata: Metadata{Status: StatusProposed}})
+	if got := issueIDs(issues); slices.Contains(got, "CTAC011_ACCEPTED_WITH_ERRORS") {
+		t.Fatalf("got %v but we wanted no CTAC011 for a proposed argument", got)
+	}
+}
@@ -0,0 +1,918 @@
+package ctac
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A small expression language over the Argument model, used by custom rules:
+//
+//	count(premises where confidence == 'low') > len(premises)/2 && conclusion.modality == 'must'
+//
+// Expressions are parsed and type-checked when the config is loaded and can
+// only read the argument they are evaluated against. Identifiers resolve to
+// fields of the argument (title, language, premises, conclusion); inside a
+// `where` filter they first resolve to fields of the list element.
+//
+// Functions:
+//
+//	len(list|string) number      count(list) number        any(list) bool
+//	matches(string, 'regex') bool                          contains(string, string) bool
+//	lower(string) string         sources(premise|list of premises) list of sources
+
+const maxExpressionLength = 4096
+
+// ExprError reports a syntax or type error in an expression.
+type ExprError struct {
+	Source string
+	Column int
+	Msg    string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("expression %q: column %d: %s", e.Source, e.Column, e.Msg)
+}
+
+type exprKind int
+
+const (
+	kindNumber exprKind = iota
+	kindString
+	kindBool
+	kindList
+	kindArgument
+	kindPremise
+	kindConclusion
+	kindSource
+)
+
+type exprType struct {
+	kind exprKind
+	elem *exprType
+}
+
+var (
+	typeNumber     = exprType{kind: kindNumber}
+	typeString     = exprType{kind: kindString}
+	typeBool       = exprType{kind: kindBool}
+	typeArgument   = exprType{kind: kindArgument}
+	typePremise    = exprType{kind: kindPremise}
+	typeConclusion = exprType{kind: kindConclusion}
+	typeSource     = exprType{kind: kindSource}
+)
+
+func listOf(elem exprType) exprType {
+	return exprType{kind: kindList, elem: &elem}
+}
+
+func (t exprType) String() string {
+	switch t.kind {
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindBool:
+		return "bool"
+	case kindList:
+		return "list of " + t.elem.String()
+	case kindArgument:
+		return "argument"
+	case kindPremise:
+		return "premise"
+	case kindConclusion:
+		return "conclusion"
+	case kindSource:
+		return "source"
+	}
+	return "unknown"
+}
+
+func (t exprType) equal(other exprType) bool {
+	if t.kind != other.kind {
+		return false
+	}
+	if t.kind == kindList {
+		return t.elem.equal(*other.elem)
+	}
+	return true
+}
+
+// exprFields lists the fields readable on each object type.
+var exprFields = map[exprKind]map[string]exprType{
+	kindArgument: {
+		"title":      typeString,
+		"language":   typeString,
+		"premises":   listOf(typePremise),
+		"conclusion": typeConclusion,
+	},
+	kindPremise: {
+		"id":         typeString,
+		"text":       typeString,
+		"confidence": typeString,
+		"sources":    listOf(typeSource),
+	},
+	kindConclusion: {
+		"text":       typeString,
+		"modality":   typeString,
+		"confidence": typeString,
+	},
+	kindSource: {
+		"title": typeString,
+		"url":   typeString,
+		"quote": typeString,
+	},
+}
+
+// fieldValue reads a field checked against exprFields.
+func fieldValue(v any, name string) any {
+	switch o := v.(type) {
+	case Argument:
+		switch name {
+		case "title":
+			return o.Title
+		case "language":
+			return o.Language
+		case "premises":
+			list := make([]any, len(o.Premises))
+			for i, p := range o.Premises {
+				list[i] = p
+			}
+			return list
+		case "conclusion":
+			return o.Conclusion
+		}
+	case Premise:
+		switch name {
+		case "id":
+			return o.Id
+		case "text":
+			return o.Text
+		case "confidence":
+			return string(o.Confidence)
+		case "sources":
+			list := make([]any, len(o.Sources))
+			for i, s := range o.Sources {
+				list[i] = s
+			}
+			return list
+		}
+	case Conclusion:
+		switch name {
+		case "text":
+			return o.Text
+		case "modality":
+			return string(o.Modality)
+		case "confidence":
+			return string(o.Confidence)
+		}
+	case Source:
+		switch name {
+		case "title":
+			return o.Title
+		case "url":
+			return o.URL
+		case "quote":
+			return o.Quote
+		}
+	}
+	return nil
+}
+
+// Lexer
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	text  string
+	value string
+	pos   int
+}
+
+var exprOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ",", "."}
+
+func lexExpression(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	i := 0
+	for i < len(source) {
+		r, size := utf8.DecodeRuneInString(source[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(source) && (source[i] >= '0' && source[i] <= '9' || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: source[start:i], pos: start})
+		case r == '\'' || r == '"':
+			start := i
+			i += size
+			var value strings.Builder
+			closed := false
+			for i < len(source) {
+				c, n := utf8.DecodeRuneInString(source[i:])
+				i += n
+				if c == '\\' && i < len(source) {
+					escaped, m := utf8.DecodeRuneInString(source[i:])
+					value.WriteRune(escaped)
+					i += m
+					continue
+				}
+				if c == r {
+					closed = true
+					break
+				}
+				value.WriteRune(c)
+			}
+			if !closed {
+				return nil, &ExprError{Source: source, Column: start + 1, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: source[start:i], value: value.String(), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(source) {
+				c, n := utf8.DecodeRuneInString(source[i:])
+				if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+					break
+				}
+				i += n
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: source[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &ExprError{Source: source, Column: i + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, pos: len(source)}), nil
+}
+
+// Parser
+
+type exprNode interface {
+	position() int
+}
+
+type (
+	numberNode struct {
+		pos   int
+		value float64
+	}
+	stringNode struct {
+		pos   int
+		value string
+	}
+	boolNode struct {
+		pos   int
+		value bool
+	}
+	identNode struct {
+		pos   int
+		name  string
+		depth int // scopes to walk up, set by the checker
+	}
+	fieldNode struct {
+		pos    int
+		object exprNode
+		name   string
+	}
+	unaryNode struct {
+		pos     int
+		op      string
+		operand exprNode
+	}
+	binaryNode struct {
+		pos         int
+		op          string
+		left, right exprNode
+		kind        exprKind // operand kind, set by the checker
+	}
+	whereNode struct {
+		pos       int
+		list      exprNode
+		predicate exprNode
+	}
+	callNode struct {
+		pos  int
+		name string
+		args []exprNode
+		reg  *regexp.Regexp // compiled pattern of matches()
+	}
+)
+
+func (n *numberNode) position() int { return n.pos }
+func (n *stringNode) position() int { return n.pos }
+func (n *boolNode) position() int   { return n.pos }
+func (n *identNode) position() int  { return n.pos }
+func (n *fieldNode) position() int  { return n.pos }
+func (n *unaryNode) position() int  { return n.pos }
+func (n *binaryNode) position() int { return n.pos }
+func (n *whereNode) position() int  { return n.pos }
+func (n *callNode) position() int   { return n.pos }
+
+type exprParser struct {
+	source string
+	tokens []exprToken
+	next   int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.next]
+}
+
+func (p *exprParser) advance() exprToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *exprParser) errorf(pos int, format string, args ...any) error {
+	return &ExprError{Source: p.source, Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isOp reports whether the next token is one of ops; the words and, or and
+// not are accepted for &&, || and !.
+func (p *exprParser) isOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	text := tok.text
+	if tok.kind == tokIdent {
+		switch text {
+		case "and":
+			text = "&&"
+		case "or":
+			text = "||"
+		case "not":
+			text = "!"
+		default:
+			return "", false
+		}
+	} else if tok.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if text == op {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(op string) error {
+	tok := p.advance()
+	if tok.kind != tokOp || tok.text != op {
+		return p.errorf(tok.pos, "expected %q, found %s", op, describeToken(tok))
+	}
+	return nil
+}
+
+func describeToken(tok exprToken) string {
+	if tok.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", tok.text)
+}
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	list, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == tokIdent && tok.text == "where" {
+		p.advance()
+		predicate, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		return &whereNode{pos: tok.pos, list: list, predicate: predicate}, nil
+	}
+	return list, nil
+}
+
+var exprPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseBinary(level int) (exprNode, error) {
+	if level == len(exprPrecedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.isOp(exprPrecedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		tok := p.advance()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{pos: tok.pos, op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.isOp("!", "-"); ok {
+		tok := p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{pos: tok.pos, op: op, operand: operand}, nil
+	}
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.isOp("."); !ok {
+			return node, nil
+		}
+		p.advance()
+		tok := p.advance()
+		if tok.kind != tokIdent {
+			return nil, p.errorf(tok.pos, "expected a field name after '.', found %s", describeToken(tok))
+		}
+		node = &fieldNode{pos: tok.pos, object: node, name: tok.text}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok.pos, "invalid number %q", tok.text)
+		}
+		return &numberNode{pos: tok.pos, value: value}, nil
+	case tokString:
+		return &stringNode{pos: tok.pos, value: tok.value}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true", "false":
+			return &boolNode{pos: tok.pos, value: tok.text == "true"}, nil
+		}
+		if _, ok := p.isOp("("); !ok {
+			return &identNode{pos: tok.pos, name: tok.text}, nil
+		}
+		p.advance()
+		call := &callNode{pos: tok.pos, name: tok.text}
+		if _, ok := p.isOp(")"); ok {
+			p.advance()
+			return call, nil
+		}
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if _, ok := p.isOp(","); !ok {
+				break
+			}
+			p.advance()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return call, nil
+	case tokOp:
+		if tok.text == "(" {
+			node, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		}
+	}
+	return nil, p.errorf(tok.pos, "unexpected %s", describeToken(tok))
+}
+
+// Type checker
+
+type exprScope struct {
+	kind   exprKind
+	parent *exprScope
+}
+
+type exprChecker struct {
+	source string
+}
+
+func (c *exprChecker) errorf(node exprNode, format string, args ...any) error {
+	return &ExprError{Source: c.source, Column: node.position() + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (c *exprChecker) check(node exprNode, scope *exprScope) (exprType, error) {
+	switch n := node.(type) {
+	case *numberNode:
+		return typeNumber, nil
+	case *stringNode:
+		return typeString, nil
+	case *boolNode:
+		return typeBool, nil
+	case *identNode:
+		depth := 0
+		for s := scope; s != nil; s = s.parent {
+			if t, ok := exprFields[s.kind][n.name]; ok {
+				n.depth = depth
+				return t, nil
+			}
+			depth++
+		}
+		return exprType{}, c.errorf(n, "unknown identifier %q", n.name)
+	case *fieldNode:
+		objectType, err := c.check(n.object, scope)
+		if err != nil {
+			return exprType{}, err
+		}
+		fields, ok := exprFields[objectType.kind]
+		if !ok {
+			return exprType{}, c.errorf(n, "%s has no field %q", objectType, n.name)
+		}
+		t, ok := fields[n.name]
+		if !ok {
+			return exprType{}, c.errorf(n, "%s has no field %q", objectType, n.name)
+		}
+		return t, nil
+	case *unaryNode:
+		t, err := c.check(n.operand, scope)
+		if err != nil {
+			return exprType{}, err
+		}
+		want := typeBool
+		if n.op == "-" {
+			want = typeNumber
+		}
+		if !t.equal(want) {
+			return exprType{}, c.errorf(n, "operator %s expects a %s, got %s", n.op, want, t)
+		}
+		return want, nil
+	case *binaryNode:
+		left, err := c.check(n.left, scope)
+		if err != nil {
+			return exprType{}, err
+		}
+		right, err := c.check(n.right, scope)
+		if err != nil {
+			return exprType{}, err
+		}
+		if !left.equal(right) {
+			return exprType{}, c.errorf(n, "operator %s cannot compare %s with %s", n.op, left, right)
+		}
+		n.kind = left.kind
+		switch n.op {
+		case "&&", "||":
+			if left.kind != kindBool {
+				return exprType{}, c.errorf(n, "operator %s expects bools, got %s", n.op, left)
+			}
+			return typeBool, nil
+		case "==", "!=":
+			if left.kind != kindBool && left.kind != kindNumber && left.kind != kindString {
+				return exprType{}, c.errorf(n, "operator %s cannot compare values of type %s", n.op, left)
+			}
+			return typeBool, nil
+		case "<", "<=", ">", ">=":
+			if left.kind != kindNumber && left.kind != kindString {
+				return exprType{}, c.errorf(n, "operator %s cannot order values of type %s", n.op, left)
+			}
+			return typeBool, nil
+		case "+":
+			if left.kind != kindNumber && left.kind != kindString {
+				return exprType{}, c.errorf(n, "operator + cannot add values of type %s", left)
+			}
+			return left, nil
+		default:
+			if left.kind != kindNumber {
+				return exprType{}, c.errorf(n, "operator %s expects numbers, got %s", n.op, left)
+			}
+			return typeNumber, nil
+		}
+	case *whereNode:
+		listType, err := c.check(n.list, scope)
+		if err != nil {
+			return exprType{}, err
+		}
+		if listType.kind != kindList {
+			return exprType{}, c.errorf(n, "where expects a list on its left, got %s", listType)
+		}
+		predicate, err := c.check(n.predicate, &exprScope{kind: listType.elem.kind, parent: scope})
+		if err != nil {
+			return exprType{}, err
+		}
+		if predicate.kind != kindBool {
+			return exprType{}, c.errorf(n.predicate, "where expects a bool condition, got %s", predicate)
+		}
+		return listType, nil
+	case *callNode:
+		return c.checkCall(n, scope)
+	}
+	return exprType{}, c.errorf(node, "unsupported expression")
+}
+
+func (c *exprChecker) checkCall(n *callNode, scope *exprScope) (exprType, error) {
+	args := make([]exprType, len(n.args))
+	for i, arg := range n.args {
+		t, err := c.check(arg, scope)
+		if err != nil {
+			return exprType{}, err
+		}
+		args[i] = t
+	}
+
+	arity := func(want int) error {
+		if len(args) != want {
+			return c.errorf(n, "%s expects %d argument%s, got %d", n.name, want, plural(want), len(args))
+		}
+		return nil
+	}
+
+	switch n.name {
+	case "len":
+		if err := arity(1); err != nil {
+			return exprType{}, err
+		}
+		if args[0].kind != kindList && args[0].kind != kindString {
+			return exprType{}, c.errorf(n.args[0], "len expects a list or a string, got %s", args[0])
+		}
+		return typeNumber, nil
+	case "count", "any":
+		if err := arity(1); err != nil {
+			return exprType{}, err
+		}
+		if args[0].kind != kindList {
+			return exprType{}, c.errorf(n.args[0], "%s expects a list, got %s", n.name, args[0])
+		}
+		if n.name == "any" {
+			return typeBool, nil
+		}
+		return typeNumber, nil
+	case "matches", "contains":
+		if err := arity(2); err != nil {
+			return exprType{}, err
+		}
+		for i, t := range args {
+			if t.kind != kindString {
+				return exprType{}, c.errorf(n.args[i], "%s expects strings, got %s", n.name, t)
+			}
+		}
+		if n.name == "matches" {
+			pattern, ok := n.args[1].(*stringNode)
+			if !ok {
+				return exprType{}, c.errorf(n.args[1], "matches expects a string literal pattern")
+			}
+			reg, err := regexp.Compile("(?i)" + pattern.value)
+			if err != nil {
+				return exprType{}, c.errorf(n.args[1], "invalid pattern: %v", err)
+			}
+			n.reg = reg
+		}
+		return typeBool, nil
+	case "lower":
+		if err := arity(1); err != nil {
+			return exprType{}, err
+		}
+		if args[0].kind != kindString {
+			return exprType{}, c.errorf(n.args[0], "lower expects a string, got %s", args[0])
+		}
+		return typeString, nil
+	case "sources":
+		if err := arity(1); err != nil {
+			return exprType{}, err
+		}
+		if !args[0].equal(typePremise) && !args[0].equal(listOf(typePremise)) {
+			return exprType{}, c.errorf(n.args[0], "sources expects a premise or a list of premises, got %s", args[0])
+		}
+		return listOf(typeSource), nil
+	}
+	return exprType{}, c.errorf(n, "unknown function %q", n.name)
+}
+
+// Evaluator
+
+type exprEnv struct {
+	value  any
+	parent *exprEnv
+}
+
+func evalExpr(node exprNode, env *exprEnv) any {
+	switch n := node.(type) {
+	case *numberNode:
+		return n.value
+	case *stringNode:
+		return n.value
+	case *boolNode:
+		return n.value
+	case *identNode:
+		e := env
+		for i := 0; i < n.depth; i++ {
+			e = e.parent
+		}
+		return fieldValue(e.value, n.name)
+	case *fieldNode:
+		return fieldValue(evalExpr(n.object, env), n.name)
+	case *unaryNode:
+		if n.op == "-" {
+			return -evalExpr(n.operand, env).(float64)
+		}
+		return !evalExpr(n.operand, env).(bool)
+	case *binaryNode:
+		return evalBinary(n, env)
+	case *whereNode:
+		var kept []any
+		for _, item := range evalExpr(n.list, env).([]any) {
+			if evalExpr(n.predicate, &exprEnv{value: item, parent: env}).(bool) {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	case *callNode:
+		return evalCall(n, env)
+	}
+	return nil
+}
+
+func evalBinary(n *binaryNode, env *exprEnv) any {
+	switch n.op {
+	case "&&":
+		return evalExpr(n.left, env).(bool) && evalExpr(n.right, env).(bool)
+	case "||":
+		return evalExpr(n.left, env).(bool) || evalExpr(n.right, env).(bool)
+	}
+
+	left, right := evalExpr(n.left, env), evalExpr(n.right, env)
+	switch n.op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+
+	if n.kind == kindString {
+		l, r := left.(string), right.(string)
+		switch n.op {
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		case "+":
+			return l + r
+		}
+		return nil
+	}
+
+	l, r := left.(float64), right.(float64)
+	switch n.op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/", "%":
+		// A zero divisor gives 0 rather than ±Inf or NaN, so expressions
+		// cannot fail at run time.
+		if r == 0 {
+			return 0.0
+		}
+		if n.op == "/" {
+			return l / r
+		}
+		return math.Mod(l, r)
+	}
+	return nil
+}
+
+func evalCall(n *callNode, env *exprEnv) any {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		args[i] = evalExpr(arg, env)
+	}
+	switch n.name {
+	case "len":
+		if s, ok := args[0].(string); ok {
+			return float64(utf8.RuneCountInString(s))
+		}
+		return float64(len(args[0].([]any)))
+	case "count":
+		return float64(len(args[0].([]any)))
+	case "any":
+		return len(args[0].([]any)) > 0
+	case "matches":
+		return n.reg.MatchString(args[0].(string))
+	case "contains":
+		return strings.Contains(strings.ToLower(args[0].(string)), strings.ToLower(args[1].(string)))
+	case "lower":
+		return strings.ToLower(args[0].(string))
+	case "sources":
+		var premises []any
+		if list, ok := args[0].([]any); ok {
+			premises = list
+		} else {
+			premises = []any{args[0]}
+		}
+		var sources []any
+		for _, p := range premises {
+			sources = append(sources, fieldValue(p, "sources").([]any)...)
+		}
+		return sources
+	}
+	return nil
+}
+
+// Expression is a parsed and type-checked boolean expression over an Argument.
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// CompileExpression parses source and checks that it is a well-typed
+// boolean expression, returning an *ExprError otherwise.
+func CompileExpression(source string) (*Expression, error) {
+	if len(source) > maxExpressionLength {
+		cut := 32
+		for !utf8.RuneStart(source[cut]) {
+			cut--
+		}
+		return nil, &ExprError{Source: source[:cut] + "…", Column: maxExpressionLength, Msg: fmt.Sprintf("expression longer than %d bytes", maxExpressionLength)}
+	}
+	tokens, err := lexExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	parser := exprParser{source: source, tokens: tokens}
+	root, err := parser.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := parser.peek(); tok.kind != tokEOF {
+		return nil, parser.errorf(tok.pos, "unexpected %s", describeToken(tok))
+	}
+
+	checker := exprChecker{source: source}
+	t, err := checker.check(root, &exprScope{kind: kindArgument})
+	if err != nil {
+		return nil, err
+	}
+	if t.kind != kindBool {
+		return nil, &ExprError{Source: source, Column: 1, Msg: fmt.Sprintf("expression must be a bool, got %s", t)}
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against an argument.
+func (e *Expression) Eval(a Argument) bool {
+	return evalExpr(e.root, &exprEnv{value: a}).(bool)
+}
@@ -0,0 +1,92 @@
+package ctac
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestExpressionEval(t *testing.T) {
+
+	argument := Argument{
+		Title: "Rewrite the billing service",
+		Premises: []Premise{
+			{Id: "P1", Text: "The billing service has 40% test coverage", Confidence: High, Sources: []Source{{Title: "Coverage report"}}},
+			{Id: "P2", Text: "Maybe the team dislikes the code", Confidence: Low},
+			{Id: "P3", Text: "The vendor library is deprecated", Confidence: Low},
+		},
+		Conclusion: Conclusion{Text: "We must rewrite the billing service", Modality: ModalityMust, Confidence: High},
+	}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "count(premises where confidence == 'low') > len(premises)/2 && conclusion.modality == 'must'", want: true},
+		{expr: "count(premises where confidence == 'high') >= 2", want: false},
+		{expr: "any(premises where matches(text, '\\\\bmaybe\\\\b'))", want: true},
+		{expr: "count(sources(premises)) == 1", want: true},
+		{expr: "count(premises where count(sources) == 0) == 2", want: true},
+		{expr: "contains(title, 'BILLING') and not (conclusion.confidence == 'low')", want: true},
+		{expr: "len(premises) % 2 == 1 || false", want: true},
+		{expr: "len(premises) % 0.5 == 0", want: true},
+		{expr: "len(premises) % 0 == 0", want: true},
+		{expr: "len(premises) / 0 == 0", want: true},
+		{expr: "lower(conclusion.text) == 'we must rewrite the billing service'", want: true},
+		{expr: "any(premises where id == 'P1' && conclusion.modality == 'could')", want: false},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CompileExpression(tc.expr)
+			if err != nil {
+				t.Fatalf("compiling: %v", err)
+			}
+			if got := expr.Eval(argument); got != tc.want {
+				t.Fatalf("got %v but we wanted %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+
+	cases := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "count(premises) > 'two'", wantErr: "cannot compare number with string"},
+		{expr: "count(title) > 1", wantErr: "count expects a list, got string"},
+		{expr: "premises where confidence", wantErr: "where expects a bool condition"},
+		{expr: "conclusion.author == 'me'", wantErr: `conclusion has no field "author"`},
+		{expr: "confidence == 'low'", wantErr: `unknown identifier "confidence"`},
+		{expr: "len(premises)", wantErr: "expression must be a bool"},
+		{expr: "matches(title, '(')", wantErr: "invalid pattern"},
+		{expr: "matches(title, conclusion.text)", wantErr: "string literal pattern"},
+		{expr: "title == 'unterminated", wantErr: "unterminated string"},
+		{expr: "shell('rm -rf /')", wantErr: `unknown function "shell"`},
+		{expr: "(len(premises) > 1", wantErr: `expected ")"`},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CompileExpression(tc.expr)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	long := "'" + strings.Repeat("é", maxExpressionLength) + "' == title"
+	var exprErr *ExprError
+	if _, err := CompileExpression(long); !errors.As(err, &exprErr) || !utf8.ValidString(exprErr.Source) {
+		t.Fatalf("got error %v but we wanted one quoting valid UTF-8 for a long expression", err)
+	}
+}
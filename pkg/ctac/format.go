@@ -0,0 +1,403 @@
+package ctac
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an encoding of the Argument schema.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+	// FormatMarkdown reads an argument embedded in a Markdown document; see
+	// DecodeMarkdown. It can be read but not written.
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats lists the formats arguments can be written in.
+var Formats = []Format{FormatYAML, FormatJSON, FormatTOML}
+
+var regexTOMLLine = regexp.MustCompile(`^(\[\[?[A-Za-z_][\w.-]*\]\]?|[A-Za-z_][\w.-]*\s*=)`)
+
+// ParseFormat parses a format name, accepting "yml" for YAML.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	case "toml":
+		return FormatTOML, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want yaml, json, toml or markdown)", name)
+}
+
+// FormatFromPath returns the format matching the extension of filePath.
+func FormatFromPath(filePath string) (Format, bool) {
+	format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(filePath), "."))
+	return format, err == nil
+}
+
+// DetectFormat sniffs the format of data: a document with a ```ctac block
+// is Markdown, an object is JSON, a first line that is a TOML table header
+// or key/value pair is TOML, and anything else YAML.
+func DetectFormat(data []byte) Format {
+	if regexCtacFence.Match(data) {
+		return FormatMarkdown
+	}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return FormatJSON
+	}
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if regexTOMLLine.MatchString(line) {
+			return FormatTOML
+		}
+		break
+	}
+	return FormatYAML
+}
+
+// argumentList is a file holding several arguments under `arguments`.
+type argumentList struct {
+	Arguments []Argument `yaml:"arguments" json:"arguments" toml:"arguments"`
+}
+
+// DecodeArgument parses and validates a single argument in the given format.
+func DecodeArgument(data []byte, format Format) (*Argument, error) {
+	arguments, err := DecodeArguments(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(arguments) != 1 {
+		return nil, fmt.Errorf("expected a single argument but found %d", len(arguments))
+	}
+	return &arguments[0], nil
+}
+
+// DecodeArguments parses and validates the arguments in data: a single
+// argument, a top-level `arguments` list (a JSON array also works) or, in
+// YAML, several `---` separated documents. Each argument gets its Index.
+func DecodeArguments(data []byte, format Format) ([]Argument, error) {
+	return decodeArguments(data, format, 0)
+}
+
+// decodeArguments is DecodeArguments for data starting after lineOffset
+// lines of its file.
+func decodeArguments(data []byte, format Format, lineOffset int) ([]Argument, error) {
+	var arguments []Argument
+	var err error
+	switch format {
+	case FormatYAML:
+		arguments, err = decodeYAMLArguments(data, lineOffset)
+	case FormatMarkdown:
+		return decodeMarkdown(data)
+	case FormatJSON:
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+			err = json.Unmarshal(data, &arguments)
+			break
+		}
+		list := argumentList{}
+		if err = json.Unmarshal(data, &list); err == nil {
+			arguments = list.Arguments
+		}
+		if err == nil && arguments == nil {
+			arguments = make([]Argument, 1)
+			err = json.Unmarshal(data, &arguments[0])
+		}
+	case FormatTOML:
+		list := argumentList{}
+		if err = toml.Unmarshal(data, &list); err == nil {
+			arguments = list.Arguments
+		}
+		if err == nil && arguments == nil {
+			arguments = make([]Argument, 1)
+			err = toml.Unmarshal(data, &arguments[0])
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", format, err)
+	}
+	if len(arguments) == 0 {
+		arguments = make([]Argument, 1)
+	}
+
+	for i := range arguments {
+		arguments[i].Index = i
+		if err := arguments[i].Validate(); err != nil {
+			if len(arguments) > 1 {
+				return nil, fmt.Errorf("arguments[%d] (%q): %w", i, arguments[i].Title, err)
+			}
+			return nil, err
+		}
+	}
+	return arguments, nil
+}
+
+// decodeYAMLArguments reads every document of a YAML stream, expanding
+// documents that hold an `arguments` list.
+func decodeYAMLArguments(data []byte, lineOffset int) ([]Argument, error) {
+	var arguments []Argument
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				return arguments, nil
+			}
+			return nil, err
+		}
+		if len(document.Content) == 0 || document.Content[0].Tag == "!!null" {
+			continue
+		}
+
+		root := document.Content[0]
+		nodes := []*yaml.Node{root}
+		if list := yamlValue(root, "arguments"); list != nil && list.Kind == yaml.SequenceNode {
+			nodes = list.Content
+		}
+		for _, node := range nodes {
+			argument := Argument{}
+			if err := node.Decode(&argument); err != nil {
+				return nil, err
+			}
+			setLines(&argument, node, lineOffset)
+			arguments = append(arguments, argument)
+		}
+	}
+}
+
+// yamlValue returns the value of key in a mapping node.
+func yamlValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// EncodeArgument writes an argument in the given format.
+func EncodeArgument(argument *Argument, format Format) ([]byte, error) {
+	return encode(argument, format)
+}
+
+// EncodeArguments writes arguments in the given format, as a single
+// argument or as an `arguments` list.
+func EncodeArguments(arguments []Argument, format Format) ([]byte, error) {
+	if len(arguments) == 1 {
+		return encode(&arguments[0], format)
+	}
+	return encode(argumentList{Arguments: arguments}, format)
+}
+
+func encode(value any, format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(value)
+	case FormatJSON:
+		data, err := json.MarshalIndent(value, "", "  ")
+		return append(data, '\n'), err
+	case FormatTOML:
+		var buf bytes.Buffer
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = ""
+		err := encoder.Encode(value)
+		return buf.Bytes(), err
+	case FormatMarkdown:
+		return nil, fmt.Errorf("arguments cannot be written as %s", format)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// setLines records where the argument, its premises and its conclusion
+// start, shifting the lines of node by offset.
+func setLines(argument *Argument, node *yaml.Node, offset int) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	argument.Line = node.Line + offset
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case "premises":
+			for j, item := range value.Content {
+				if j < len(argument.Premises) {
+					argument.Premises[j].Line = item.Line + offset
+				}
+			}
+		case "conclusion":
+			argument.Conclusion.Line = key.Line + offset
+		case "criteria":
+			for j, item := range value.Content {
+				if j < len(argument.Criteria) {
+					argument.Criteria[j].Line = item.Line + offset
+				}
+			}
+		case "alternatives":
+			for j, item := range value.Content {
+				if j >= len(argument.Alternatives) {
+					break
+				}
+				alternative := &argument.Alternatives[j]
+				alternative.Line = item.Line + offset
+				for _, list := range []struct {
+					key      string
+					premises []Premise
+				}{{"pros", alternative.Pros}, {"cons", alternative.Cons}} {
+					if items := yamlValue(item, list.key); items != nil {
+						for k, entry := range items.Content {
+							if k < len(list.premises) {
+								list.premises[k].Line = entry.Line + offset
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Validate reports premises without a unique ID and unknown confidence or
+// modality values. A premise with a Ref may leave its ID to the shared one. Missing premises or conclusions are left to the rules.
+func (a Argument) Validate() error {
+	var errs []error
+	seen := make(map[string]bool, len(a.Premises))
+	for i, premise := range a.Premises {
+		switch {
+		case premise.Id == "" && premise.Ref != "":
+			// Takes the ID of the shared premise.
+		case premise.Id == "":
+			errs = append(errs, fmt.Errorf("premises[%d]: id is required", i))
+		case seen[premise.Id]:
+			errs = append(errs, fmt.Errorf("premises[%d]: duplicate id %q", i, premise.Id))
+		}
+		seen[premise.Id] = true
+		if !premise.Confidence.valid() {
+			errs = append(errs, fmt.Errorf("premises[%d]: unknown confidence %q (want low, medium or high)", i, premise.Confidence))
+		}
+	}
+	if !a.Conclusion.Confidence.valid() {
+		errs = append(errs, fmt.Errorf("conclusion: unknown confidence %q (want low, medium or high)", a.Conclusion.Confidence))
+	}
+	switch a.Conclusion.Modality {
+	case "", ModalityMust, ModalityShould, ModalityCould:
+	default:
+		errs = append(errs, fmt.Errorf("conclusion: unknown modality %q (want must, should or could)", a.Conclusion.Modality))
+	}
+	criteria := make(map[string]bool, len(a.Criteria))
+	for i, criterion := range a.Criteria {
+		switch {
+		case strings.TrimSpace(criterion.Name) == "":
+			errs = append(errs, fmt.Errorf("criteria[%d]: name is required", i))
+		case criteria[criterion.Name]:
+			errs = append(errs, fmt.Errorf("criteria[%d]: duplicate name %q", i, criterion.Name))
+		}
+		criteria[criterion.Name] = true
+		if criterion.Weight <= 0 {
+			errs = append(errs, fmt.Errorf("criteria[%d]: weight must be positive, got %v", i, criterion.Weight))
+		}
+	}
+
+	names := make(map[string]bool, len(a.Alternatives))
+	for i, alternative := range a.Alternatives {
+		switch {
+		case strings.TrimSpace(alternative.Name) == "":
+			errs = append(errs, fmt.Errorf("alternatives[%d]: name is required", i))
+		case names[alternative.Name]:
+			errs = append(errs, fmt.Errorf("alternatives[%d]: duplicate name %q", i, alternative.Name))
+		}
+		names[alternative.Name] = true
+		scored := make([]string, 0, len(alternative.Scores))
+		for name := range alternative.Scores {
+			scored = append(scored, name)
+		}
+		sort.Strings(scored)
+		for _, name := range scored {
+			if !criteria[name] {
+				errs = append(errs, fmt.Errorf("alternatives[%d].scores: unknown criterion %q", i, name))
+			}
+		}
+		for _, criterion := range a.Criteria {
+			if _, ok := alternative.Scores[criterion.Name]; !ok && criterion.Name != "" {
+				errs = append(errs, fmt.Errorf("alternatives[%d].scores: no score for criterion %q", i, criterion.Name))
+			}
+		}
+		for _, list := range []struct {
+			name     string
+			premises []Premise
+		}{{"pros", alternative.Pros}, {"cons", alternative.Cons}} {
+			for j, premise := range list.premises {
+				if strings.TrimSpace(premise.Text) == "" {
+					errs = append(errs, fmt.Errorf("alternatives[%d].%s[%d]: text is required", i, list.name, j))
+				}
+				if !premise.Confidence.valid() {
+					errs = append(errs, fmt.Errorf("alternatives[%d].%s[%d]: unknown confidence %q (want low, medium or high)", i, list.name, j, premise.Confidence))
+				}
+			}
+		}
+	}
+	return errors.Join(append(errs, a.Metadata.validate()...)...)
+}
+
+// validate reports unknown statuses, a decision date on a proposal, and
+// blank or repeated entries in the lists.
+func (m Metadata) validate() []error {
+	var errs []error
+	switch m.Status {
+	case "", StatusProposed, StatusAccepted, StatusSuperseded, StatusRejected:
+	default:
+		errs = append(errs, fmt.Errorf("status: unknown status %q (want proposed, accepted, superseded or rejected)", m.Status))
+	}
+	if m.Status == StatusProposed && !m.DecidedAt.IsZero() {
+		errs = append(errs, fmt.Errorf("decidedAt: a proposed decision has no decision date yet"))
+	}
+	for _, list := range []struct {
+		name    string
+		entries []string
+	}{{"authors", m.Authors}, {"reviewers", m.Reviewers}, {"tags", m.Tags}, {"supersedes", m.Supersedes}} {
+		seen := make(map[string]bool, len(list.entries))
+		for i, entry := range list.entries {
+			switch {
+			case strings.TrimSpace(entry) == "":
+				errs = append(errs, fmt.Errorf("%s[%d]: must not be empty", list.name, i))
+			case seen[entry]:
+				errs = append(errs, fmt.Errorf("%s[%d]: duplicate %q", list.name, i, entry))
+			}
+			seen[entry] = true
+		}
+	}
+	return errs
+}
+
+func (c Confidence) valid() bool {
+	switch c {
+	case "", Low, Medium, High:
+		return true
+	}
+	return false
+}
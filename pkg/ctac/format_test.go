@@ -0,0 +1,250 @@
+package ctac
+
+import (
+	"reflect"
+	"testing"
+)
+
+const yamlArgument = `title: "Formats"
+premises:
+-   id: P1
+    text: "Tooling emits JSON"
+    confidence: high
+    sources:
+    -   title: "Build logs"
+        url: "https://example.com/logs"
+conclusion:
+    text: "We should accept JSON and TOML"
+    modality: should
+    confidence: medium
+`
+
+const jsonArgument = `{
+  "title": "Formats",
+  "premises": [
+    {"id": "P1", "text": "Tooling emits JSON", "confidence": "high",
+     "sources": [{"title": "Build logs", "url": "https://example.com/logs"}]}
+  ],
+  "conclusion": {"text": "We should accept JSON and TOML", "modality": "should", "confidence": "medium"}
+}`
+
+const tomlArgument = `# A TOML argument
+title = "Formats"
+
+[[premises]]
+id = "P1"
+text = "Tooling emits JSON"
+confidence = "high"
+
+[[premises.sources]]
+title = "Build logs"
+url = "https://example.com/logs"
+
+[conclusion]
+text = "We should accept JSON and TOML"
+modality = "should"
+confidence = "medium"
+`
+
+func TestFormats(t *testing.T) {
+
+	want, err := DecodeArgument([]byte(yamlArgument), FormatYAML)
+	if err != nil {
+		t.Fatalf("decoding YAML: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		input  string
+		format Format
+	}{
+		{name: "YAML is sniffed", input: yamlArgument, format: FormatYAML},
+		{name: "JSON is sniffed", input: jsonArgument, format: FormatJSON},
+		{name: "TOML is sniffed", input: tomlArgument, format: FormatTOML},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tc.input)); got != tc.format {
+				t.Fatalf("got format %s but we wanted %s", got, tc.format)
+			}
+			got, err := LoadBytes([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("loading: %v", err)
+			}
+			if !reflect.DeepEqual(withoutLines(got), withoutLines(want)) {
+				t.Fatalf("got %+v but we wanted %+v", got, want)
+			}
+
+			for _, format := range Formats {
+				encoded, err := EncodeArgument(got, format)
+				if err != nil {
+					t.Fatalf("encoding %s: %v", format, err)
+				}
+				roundTrip, err := DecodeArgument(encoded, format)
+				if err != nil {
+					t.Fatalf("decoding %s: %v\n%s", format, err, encoded)
+				}
+				if !reflect.DeepEqual(withoutLines(roundTrip), withoutLines(want)) {
+					t.Fatalf("%s round trip got %+v but we wanted %+v", format, roundTrip, want)
+				}
+			}
+		})
+	}
+}
+
+// withoutLines clears the line numbers, which only YAML input has.
+func withoutLines(argument *Argument) Argument {
+	stripped := *argument
+	stripped.Line, stripped.Conclusion.Line = 0, 0
+	stripped.Premises = append([]Premise(nil), argument.Premises...)
+	for i := range stripped.Premises {
+		stripped.Premises[i].Line = 0
+	}
+	return stripped
+}
+
+func TestYAMLLines(t *testing.T) {
+
+	argument, err := DecodeArgument([]byte(yamlArgument), FormatYAML)
+	if err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if argument.Line != 1 || argument.Premises[0].Line != 3 || argument.Conclusion.Line != 9 {
+		t.Fatalf("got lines %d, %d and %d but we wanted 1, 3 and 9", argument.Line, argument.Premises[0].Line, argument.Conclusion.Line)
+	}
+}
+
+func TestValidationErrorsMatchAcrossFormats(t *testing.T) {
+
+	inputs := map[Format]string{
+		FormatYAML: "premises:\n-   id: P1\n    confidence: sure\n-   id: P1\nconclusion:\n    modality: might\n",
+		FormatJSON: `{"premises": [{"id": "P1", "confidence": "sure"}, {"id": "P1"}], "conclusion": {"modality": "might"}}`,
+		FormatTOML: "[[premises]]\nid = \"P1\"\nconfidence = \"sure\"\n[[premises]]\nid = \"P1\"\n[conclusion]\nmodality = \"might\"\n",
+	}
+	want := `premises[0]: unknown confidence "sure" (want low, medium or high)
+premises[1]: duplicate id "P1"
+conclusion: unknown modality "might" (want must, should or could)`
+
+	for format, input := range inputs {
+		_, err := DecodeArgument([]byte(input), format)
+		if err == nil || err.Error() != want {
+			t.Fatalf("%s: got error %v but we wanted %q", format, err, want)
+		}
+	}
+}
+
+func TestDecodeArguments(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		input      string
+		format     Format
+		wantTitles []string
+		wantLines  []int
+		wantErr    string
+	}{
+		{
+			name:       "Multi-document YAML",
+			input:      "title: First\npremises:\n-   id: P1\n---\ntitle: Second\n---\n",
+			format:     FormatYAML,
+			wantTitles: []string{"First", "Second"},
+			wantLines:  []int{1, 5},
+		},
+		{
+			name:       "YAML arguments list",
+			input:      "arguments:\n-   title: First\n-   title: Second\n    premises:\n    -   id: P1\n",
+			format:     FormatYAML,
+			wantTitles: []string{"First", "Second"},
+			wantLines:  []int{2, 3},
+		},
+		{name: "JSON array", input: `[{"title": "First"}, {"title": "Second"}]`, format: FormatJSON, wantTitles: []string{"First", "Second"}, wantLines: []int{0, 0}},
+		{name: "JSON arguments list", input: `{"arguments": [{"title": "First"}]}`, format: FormatJSON, wantTitles: []string{"First"}, wantLines: []int{0}},
+		{name: "TOML arguments list", input: "[[arguments]]\ntitle = \"First\"\n[[arguments]]\ntitle = \"Second\"\n", format: FormatTOML, wantTitles: []string{"First", "Second"}, wantLines: []int{0, 0}},
+		{
+			name:    "Validation errors name the argument",
+			input:   "title: First\n---\ntitle: Second\npremises:\n-   text: x\n",
+			format:  FormatYAML,
+			wantErr: `arguments[1] ("Second"): premises[0]: id is required`,
+		},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			arguments, err := DecodeArguments([]byte(tc.input), tc.format)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("got error %v but we wanted %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decoding: %v", err)
+			}
+			if len(arguments) != len(tc.wantTitles) {
+				t.Fatalf("got %d arguments but we wanted %d", len(arguments), len(tc.wantTitles))
+			}
+			for i, argument := range arguments {
+				if argument.Title != tc.wantTitles[i] || argument.Index != i || argument.Line != tc.wantLines[i] {
+					t.Fatalf("got argument %d %q at index %d line %d but we wanted %q at line %d", i, argument.Title, argument.Index, argument.Line, tc.wantTitles[i], tc.wantLines[i])
+				}
+			}
+
+			if len(arguments) > 1 {
+				if _, err := DecodeArgument([]byte(tc.input), tc.format); err == nil {
+					t.Fatalf("expected DecodeArgument to reject several arguments")
+				}
+				encoded, err := EncodeArguments(arguments, tc.format)
+				if err != nil {
+					t.Fatalf("encoding: %v", err)
+				}
+				if roundTrip, err := DecodeArguments(encoded, tc.format); err != nil || len(roundTrip) != len(arguments) {
+					t.Fatalf("round trip got %d arguments and error %v\n%s", len(roundTrip), err, encoded)
+				}
+			}
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "Valid metadata", input: "title: T\nstatus: superseded\nauthors: [ana, ben]\ndecidedAt: 2026-02-01\nreviewers: [cy]\ntags: [infra]\nsupersedes: [adr/0003.yaml]\n"},
+		{name: "Unknown status", input: "status: done\n", wantErr: `status: unknown status "done" (want proposed, accepted, superseded or rejected)`},
+		{name: "Proposals have no decision date", input: "status: proposed\ndecidedAt: 2026-02-01\n", wantErr: "decidedAt: a proposed decision has no decision date yet"},
+		{name: "Duplicate authors", input: "authors: [ana, ana]\n", wantErr: `authors[1]: duplicate "ana"`},
+		{name: "Empty tags", input: "tags: [' ']\n", wantErr: "tags[0]: must not be empty"},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			argument, err := DecodeArgument([]byte(tc.input), FormatYAML)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("got error %v but we wanted %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decoding: %v", err)
+			}
+			for _, format := range Formats {
+				encoded, err := EncodeArgument(argument, format)
+				if err != nil {
+					t.Fatalf("encoding %s: %v", format, err)
+				}
+				roundTrip, err := DecodeArgument(encoded, format)
+				if err != nil {
+					t.Fatalf("decoding %s: %v\n%s", format, err, encoded)
+				}
+				if !reflect.DeepEqual(roundTrip.Metadata, argument.Metadata) {
+					t.Fatalf("%s round trip got %+v but we wanted %+v", format, roundTrip.Metadata, argument.Metadata)
+				}
+			}
+		})
+	}
+}
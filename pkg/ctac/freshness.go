@@ -0,0 +1,65 @@
+package ctac
+
+import (
+	"fmt"
+	"time"
+)
+
+const defaultMaxEvidenceAgeDays = 365
+
+// StaleEvidence is a premise, or a source cited by a premise, that is past
+// its expiry date or older than the maximum age.
+type StaleEvidence struct {
+	PremiseID string
+	// Source is the title of the stale source, or empty when the premise
+	// itself is stale.
+	Source  string
+	Line    int
+	AsOf    Date
+	Expires Date
+	Expired bool
+	// AgeDays is how old the evidence is, when AsOf is set.
+	AgeDays int
+}
+
+func (s StaleEvidence) String() string {
+	what := "Premise " + s.PremiseID
+	if s.Source != "" {
+		what = fmt.Sprintf("Source %q of premise %s", s.Source, s.PremiseID)
+	}
+	if s.Expired {
+		return fmt.Sprintf("%s expired on %s", what, s.Expires)
+	}
+	return fmt.Sprintf("%s is %d days old (as of %s)", what, s.AgeDays, s.AsOf)
+}
+
+// FindStaleEvidence returns the stale premises and sources of an argument.
+// Evidence expires at the end of its Expires day; a zero maxAgeDays uses the
+// default of a year and a zero today the current date.
+func FindStaleEvidence(argument Argument, maxAgeDays int, today Date) []StaleEvidence {
+	if maxAgeDays <= 0 {
+		maxAgeDays = defaultMaxEvidenceAgeDays
+	}
+	if today.IsZero() {
+		today = DateOf(time.Now())
+	}
+
+	var stale []StaleEvidence
+	check := func(evidence StaleEvidence) {
+		evidence.Expired = !evidence.Expires.IsZero() && evidence.Expires.Before(today.Time)
+		if !evidence.AsOf.IsZero() {
+			evidence.AgeDays = int(today.Sub(evidence.AsOf.Time).Hours() / 24)
+		}
+		if evidence.Expired || evidence.AgeDays > maxAgeDays {
+			stale = append(stale, evidence)
+		}
+	}
+
+	for _, p := range argument.Premises {
+		check(StaleEvidence{PremiseID: p.Id, Line: p.Line, AsOf: p.AsOf, Expires: p.Expires})
+		for _, source := range p.Sources {
+			check(StaleEvidence{PremiseID: p.Id, Source: source.Title, Line: p.Line, AsOf: source.AsOf, Expires: source.Expires})
+		}
+	}
+	return stale
+}
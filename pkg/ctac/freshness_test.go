@@ -0,0 +1,82 @@
+package ctac
+
+import (
+	"testing"
+)
+
+func mustDate(t *testing.T, s string) Date {
+	t.Helper()
+	d, err := ParseDate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestStaleEvidenceRule(t *testing.T) {
+
+	today := mustDate(t, "2026-06-01")
+
+	cases := []struct {
+		name         string
+		maxAgeDays   int
+		premise      Premise
+		wantMessages []string
+	}{
+		{
+			name:    "Recent evidence is fine",
+			premise: Premise{Id: "P1", AsOf: mustDate(t, "2026-01-01"), Expires: mustDate(t, "2026-06-01")},
+		},
+		{
+			name:         "Expired premises",
+			premise:      Premise{Id: "P1", Expires: mustDate(t, "2026-05-31")},
+			wantMessages: []string{"Premise P1 expired on 2026-05-31"},
+		},
+		{
+			name:         "Old sources",
+			premise:      Premise{Id: "P1", Sources: []Source{{Title: "Traffic report", AsOf: mustDate(t, "2025-01-01")}}},
+			wantMessages: []string{`Source "Traffic report" of premise P1 is 516 days old (as of 2025-01-01)`},
+		},
+		{
+			name:         "Custom maximum age",
+			maxAgeDays:   90,
+			premise:      Premise{Id: "P1", AsOf: mustDate(t, "2026-01-01")},
+			wantMessages: []string{"Premise P1 is 151 days old (as of 2026-01-01)"},
+		},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			issues := StaleEvidenceRule{MaxAgeDays: tc.maxAgeDays, Today: today}.Check(Argument{Premises: []Premise{tc.premise}})
+			if len(issues) != len(tc.wantMessages) {
+				t.Fatalf("got %d issue%s but we wanted %d: %v", len(issues), plural(len(issues)), len(tc.wantMessages), issues)
+			}
+			for i, issue := range issues {
+				if issue.Message != tc.wantMessages[i] {
+					t.Fatalf("got %q but we wanted %q", issue.Message, tc.wantMessages[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvidenceDatesInEveryFormat(t *testing.T) {
+
+	inputs := map[Format]string{
+		FormatYAML: "premises:\n-   id: P1\n    asOf: 2025-01-02\n",
+		FormatJSON: `{"premises": [{"id": "P1", "asOf": "2025-01-02"}]}`,
+		FormatTOML: "[[premises]]\nid = \"P1\"\nasOf = 2025-01-02\n",
+	}
+	for format, input := range inputs {
+		argument, err := DecodeArgument([]byte(input), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if got := argument.Premises[0].AsOf.String(); got != "2025-01-02" {
+			t.Fatalf("%s: got asOf %q but we wanted 2025-01-02", format, got)
+		}
+	}
+	if _, err := DecodeArgument([]byte("premises:\n-   id: P1\n    expires: soon\n"), FormatYAML); err == nil {
+		t.Fatalf("expected an error for an invalid date")
+	}
+}
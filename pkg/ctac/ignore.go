@@ -11,19 +11,21 @@ type IgnoreSpec struct {
 	Reason []string `yaml:"reason" json:"reason"`
 }
 
+var defaultIgnoreFiles = []string{
+	"ctac.ignore.yaml",
+	"ctacignore.yaml",
+	"ctacIgnore.yaml",
+	"ctac.ignore.yml",
+	"ctacignore.yml",
+	"ctacIgnore.yml",
+}
+
 func resolveIgnorePath(userPath string) string {
 	if userPath != "" {
 		return userPath
 	}
 
-	for _, defaultIgnoreFilePath := range []string{
-		"ctac.ignore.yaml",
-		"ctacignore.yaml",
-		"ctacIgnore.yaml",
-		"ctac.ignore.yml",
-		"ctacignore.yml",
-		"ctacIgnore.yml",
-	} {
+	for _, defaultIgnoreFilePath := range defaultIgnoreFiles {
 		if _, err := os.Stat(defaultIgnoreFilePath); err == nil {
 			return defaultIgnoreFilePath
 		}
@@ -50,3 +52,43 @@ func LoadIgnore(filePath string) (*IgnoreSpec, error) {
 	}
 	return &ignoreSpec, err
 }
+
+// Ignores reports whether issues of ruleID are ignored, and why. Reasons
+// are matched to rules by position; a single reason applies to every rule.
+func (spec *IgnoreSpec) Ignores(ruleID string) (string, bool) {
+	for i, rule := range spec.Rules {
+		if rule != ruleID {
+			continue
+		}
+		switch {
+		case i < len(spec.Reason):
+			return spec.Reason[i], true
+		case len(spec.Reason) == 1:
+			return spec.Reason[0], true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// IgnoredRule is a rule of an ignore spec and the reason it is ignored.
+type IgnoredRule struct {
+	RuleID string
+	Reason string `json:",omitempty"`
+}
+
+// IgnoredRules lists the rules of the spec with their reasons, whether or
+// not they suppressed anything.
+func (spec *IgnoreSpec) IgnoredRules() []IgnoredRule {
+	var ignored []IgnoredRule
+	seen := make(map[string]bool)
+	for _, ruleID := range spec.Rules {
+		if seen[ruleID] {
+			continue
+		}
+		seen[ruleID] = true
+		reason, _ := spec.Ignores(ruleID)
+		ignored = append(ignored, IgnoredRule{RuleID: ruleID, Reason: reason})
+	}
+	return ignored
+}
@@ -0,0 +1,414 @@
+package ctac
+
+import (
+	"embed"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Names of the lexicons that can be changed from the config file.
+const (
+	LexiconVague           = "vague"           // CTAC002_VAGUENESS_DETECTED, including hedges
+	LexiconNegativeEmotion = "negativeEmotion" // CTAC007_EMOTIONAL_LANGUAGE_DETECTED
+	LexiconPositiveEmotion = "positiveEmotion" // CTAC007_EMOTIONAL_LANGUAGE_DETECTED
+	LexiconIntensifiers    = "intensifiers"    // CTAC007_EMOTIONAL_LANGUAGE_DETECTED
+	LexiconQuantifiers     = "quantifiers"     // CTAC006_QUANTIFICATION_REQUIRED
+	LexiconDilemma         = "dilemma"         // CTAC012_FALSE_DILEMMA, either/or framing
+)
+
+var lexiconNames = []string{LexiconVague, LexiconNegativeEmotion, LexiconPositiveEmotion, LexiconIntensifiers, LexiconQuantifiers, LexiconDilemma}
+
+// DefaultLanguage is used when an argument has no language and none can be
+// detected, or when no pack exists for its language.
+const DefaultLanguage = "en"
+
+//go:embed lexicons/*.yaml
+var lexiconPackFiles embed.FS
+
+// lexiconPacks holds one YAML lexicon pack per language.
+var lexiconPacks fs.FS = lexiconPackFiles
+
+type lexiconPack struct {
+	Language  string                    `yaml:"language"`
+	Name      string                    `yaml:"name"`
+	Stopwords []string                  `yaml:"stopwords"`
+	Negations []string                  `yaml:"negations"`
+	Lexicons  map[string][]LexiconEntry `yaml:"lexicons"`
+}
+
+// LexiconEntry is a term in a lexicon. In YAML it is either a plain string
+// or a mapping with a term, an optional regex used instead of matching the
+// term as a whole word, and an optional severity overriding the rule's.
+type LexiconEntry struct {
+	Term     string   `yaml:"term" json:"term"`
+	Regex    string   `yaml:"regex" json:"regex,omitempty"`
+	Severity Severity `yaml:"severity" json:"severity,omitempty"`
+}
+
+func (e *LexiconEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&e.Term)
+	}
+	type plain LexiconEntry
+	return node.Decode((*plain)(e))
+}
+
+// LexiconChange edits a built-in lexicon. Replace, when set, swaps the whole
+// list; Remove then drops terms and Add appends new ones.
+type LexiconChange struct {
+	Add     []LexiconEntry `yaml:"add" json:"add,omitempty"`
+	Remove  []string       `yaml:"remove" json:"remove,omitempty"`
+	Replace []LexiconEntry `yaml:"replace" json:"replace,omitempty"`
+}
+
+// LexiconChanges maps lexicon names to the changes made to them. A plain
+// name changes the lexicon in every language; a name prefixed with a
+// language code, such as "it.vague", changes only that language.
+type LexiconChanges map[string]LexiconChange
+
+type lexiconPhrase struct {
+	Phrase   string
+	Reg      *regexp.Regexp
+	Severity Severity
+	// wholeWord phrases only match when not surrounded by other letters.
+	wholeWord bool
+}
+
+// languageLexicons are the compiled lexicons of one language.
+type languageLexicons struct {
+	language  string
+	stopwords map[string]bool
+	negations map[string]bool
+	phrases   map[string][]lexiconPhrase
+}
+
+// Lexicons holds the compiled word lists used by the lexicon rules, one set
+// per bundled language.
+type Lexicons struct {
+	languages map[string]*languageLexicons
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.ToLower(w)] = true
+	}
+	return set
+}
+
+func loadLexiconPacks() (map[string]*lexiconPack, error) {
+	files, err := fs.Glob(lexiconPacks, "lexicons/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	packs := make(map[string]*lexiconPack, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(lexiconPacks, file)
+		if err != nil {
+			return nil, err
+		}
+		pack := lexiconPack{}
+		if err := yaml.Unmarshal(data, &pack); err != nil {
+			return nil, fmt.Errorf("lexicon pack %s: %w", file, err)
+		}
+		if pack.Language == "" {
+			pack.Language = strings.TrimSuffix(path.Base(file), ".yaml")
+		}
+		packs[pack.Language] = &pack
+	}
+	if _, ok := packs[DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("no lexicon pack for the default language %q", DefaultLanguage)
+	}
+	return packs, nil
+}
+
+func buildPhrases(entries []LexiconEntry) ([]lexiconPhrase, error) {
+	phrases := make([]lexiconPhrase, 0, len(entries))
+	for _, e := range entries {
+		if e.Term == "" && e.Regex == "" {
+			return nil, fmt.Errorf("lexicon entry needs a term or a regex")
+		}
+		switch e.Severity {
+		case "", SeverityInfo, SeverityWarning, SeverityError:
+		default:
+			return nil, fmt.Errorf("lexicon entry %q has unknown severity %q", e.Term, e.Severity)
+		}
+
+		// Go's \b only knows ASCII letters, so whole-word matching of terms
+		// such as "più" or "weiß" is checked in locate instead.
+		pattern := "(?i)" + regexp.QuoteMeta(e.Term)
+		if e.Regex != "" {
+			pattern = "(?i)" + e.Regex
+		}
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("lexicon entry %q: %w", e.Term, err)
+		}
+
+		phrase := e.Term
+		if phrase == "" {
+			phrase = e.Regex
+		}
+		phrases = append(phrases, lexiconPhrase{
+			Phrase:    phrase,
+			Reg:       reg,
+			Severity:  e.Severity,
+			wholeWord: e.Regex == "",
+		})
+	}
+	return phrases, nil
+}
+
+// locate returns the byte ranges where the phrase matches text.
+func (p lexiconPhrase) locate(text string) [][]int {
+	locs := p.Reg.FindAllStringIndex(text, -1)
+	if !p.wholeWord {
+		return locs
+	}
+	first, _ := utf8.DecodeRuneInString(p.Phrase)
+	last, _ := utf8.DecodeLastRuneInString(p.Phrase)
+	kept := locs[:0]
+	for _, loc := range locs {
+		before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+		after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+		if isWordRune(first) && isWordRune(before) || isWordRune(last) && isWordRune(after) {
+			continue
+		}
+		kept = append(kept, loc)
+	}
+	return kept
+}
+
+func applyLexiconChange(entries []LexiconEntry, change LexiconChange) []LexiconEntry {
+	if change.Replace != nil {
+		entries = append([]LexiconEntry(nil), change.Replace...)
+	}
+	if len(change.Remove) > 0 {
+		kept := entries[:0:0]
+		for _, e := range entries {
+			removed := false
+			for _, term := range change.Remove {
+				if strings.EqualFold(term, e.Term) {
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	return append(entries, change.Add...)
+}
+
+func isLexiconName(name string) bool {
+	for _, n := range lexiconNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildLexicons compiles the bundled lexicon packs with the given changes
+// applied in order.
+func BuildLexicons(changes ...LexiconChanges) (*Lexicons, error) {
+	packs, err := loadLexiconPacks()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]map[string][]LexiconEntry, len(packs))
+	for language, pack := range packs {
+		entries[language] = make(map[string][]LexiconEntry, len(lexiconNames))
+		for _, name := range lexiconNames {
+			entries[language][name] = append([]LexiconEntry(nil), pack.Lexicons[name]...)
+		}
+	}
+
+	for _, lexiconChanges := range changes {
+		keys := make([]string, 0, len(lexiconChanges))
+		for key := range lexiconChanges {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			language, name, scoped := strings.Cut(key, ".")
+			if !scoped {
+				language, name = "", key
+			}
+			if !isLexiconName(name) {
+				return nil, fmt.Errorf("unknown lexicon %q, expected one of %s", key, strings.Join(lexiconNames, ", "))
+			}
+			if scoped && entries[language] == nil {
+				return nil, fmt.Errorf("lexicon %q: no lexicon pack for language %q", key, language)
+			}
+			for lang := range entries {
+				if !scoped || lang == language {
+					entries[lang][name] = applyLexiconChange(entries[lang][name], lexiconChanges[key])
+				}
+			}
+		}
+	}
+
+	lexicons := &Lexicons{languages: make(map[string]*languageLexicons, len(packs))}
+	for language, pack := range packs {
+		compiled := &languageLexicons{
+			language:  language,
+			stopwords: wordSet(pack.Stopwords),
+			negations: wordSet(pack.Negations),
+			phrases:   make(map[string][]lexiconPhrase, len(lexiconNames)),
+		}
+		for name, list := range entries[language] {
+			phrases, err := buildPhrases(list)
+			if err != nil {
+				return nil, fmt.Errorf("lexicon %s.%s: %w", language, name, err)
+			}
+			compiled.phrases[name] = phrases
+		}
+		lexicons.languages[language] = compiled
+	}
+	return lexicons, nil
+}
+
+// LoadLexiconFile reads a lexicon file, which has the same shape as the
+// lexicons block of the config file.
+func LoadLexiconFile(filePath string) (LexiconChanges, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	changes := LexiconChanges{}
+	if err := yaml.Unmarshal(data, &changes); err != nil {
+		return nil, fmt.Errorf("lexicon file %s: %w", filePath, err)
+	}
+	return changes, nil
+}
+
+// loadLexicons compiles the lexicons of a config, reading lexicon files
+// relative to baseDir.
+func loadLexicons(config *Config, baseDir string) (*Lexicons, error) {
+	changes := []LexiconChanges{config.Lexicons}
+	for _, file := range config.LexiconFiles {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(baseDir, file)
+		}
+		fileChanges, err := LoadLexiconFile(file)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, fileChanges)
+	}
+	return BuildLexicons(changes...)
+}
+
+var defaultLexicons = func() *Lexicons {
+	lexicons, err := BuildLexicons()
+	if err != nil {
+		panic(err)
+	}
+	return lexicons
+}()
+
+// Languages returns the languages with a lexicon pack.
+func (l *Lexicons) Languages() []string {
+	if l == nil {
+		l = defaultLexicons
+	}
+	languages := make([]string, 0, len(l.languages))
+	for language := range l.languages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// DetectLanguage returns the language of the argument: its Language field
+// when set, otherwise the pack whose stop words occur most often in the
+// argument's text, and DefaultLanguage when nothing matches.
+func (l *Lexicons) DetectLanguage(a Argument) string {
+	if l == nil {
+		l = defaultLexicons
+	}
+	if a.Language != "" {
+		return strings.ToLower(a.Language)
+	}
+
+	texts := []string{a.Title, a.Conclusion.Text}
+	for _, p := range a.Premises {
+		texts = append(texts, p.Text)
+	}
+	counts := make(map[string]int, len(l.languages))
+	for _, text := range texts {
+		for _, w := range words(text) {
+			for language, lexicons := range l.languages {
+				if lexicons.stopwords[w] {
+					counts[language]++
+				}
+			}
+		}
+	}
+
+	best, bestCount := DefaultLanguage, counts[DefaultLanguage]
+	for _, language := range l.Languages() {
+		if counts[language] > bestCount {
+			best, bestCount = language, counts[language]
+		}
+	}
+	return best
+}
+
+// forArgument returns the lexicons matching the argument's language,
+// falling back to the built-in lexicons on a nil Lexicons.
+func (l *Lexicons) forArgument(a Argument) *languageLexicons {
+	if l == nil {
+		l = defaultLexicons
+	}
+	if lexicons, ok := l.languages[l.DetectLanguage(a)]; ok {
+		return lexicons
+	}
+	return l.languages[DefaultLanguage]
+}
+
+// analyse runs the text-analysis layer with the language's negation cues.
+func (l *languageLexicons) analyse(text string) analysedText {
+	return analyseText(text, l.negations)
+}
+
+// phraseNames returns the terms of the given phrases.
+func phraseNames(phrases []lexiconPhrase) []string {
+	names := make([]string, 0, len(phrases))
+	for _, p := range phrases {
+		names = append(names, p.Phrase)
+	}
+	return names
+}
+
+// phraseSeverity returns the most severe severity of the given phrases,
+// using fallback for phrases without their own.
+func phraseSeverity(phrases []lexiconPhrase, fallback Severity) Severity {
+	severity := Severity("")
+	for _, p := range phrases {
+		s := p.Severity
+		if s == "" {
+			s = fallback
+		}
+		if severity == "" || s.rank() > severity.rank() {
+			severity = s
+		}
+	}
+	if severity == "" {
+		return fallback
+	}
+	return severity
+}
@@ -1,20 +1,74 @@
 package ctac
 
 import (
-	"gopkg.in/yaml.v3"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 )
 
+// StdinPath is the file path that reads the argument from standard input.
+const StdinPath = "-"
+
+// Loader reads an argument file in the format given by its extension, or
+// the sniffed format when the extension is unknown, and resolves its refs.
 func Loader(filePath string) (*Argument, error) {
-	data, err := os.ReadFile(filePath)
+	arguments, err := LoadArguments(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(arguments) != 1 {
+		return nil, fmt.Errorf("%s: expected a single argument but found %d", filePath, len(arguments))
+	}
+	return &arguments[0], nil
+}
+
+// LoadArguments reads every argument of a file, like Loader. Refs in a file
+// read from standard input are relative to the working directory.
+func LoadArguments(filePath string) ([]Argument, error) {
+	var data []byte
+	var err error
+	if filePath == StdinPath {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := DecodeArguments(data, fileFormat(filePath, data))
 	if err != nil {
 		return nil, err
 	}
-	argument := Argument{}
-	err = yaml.Unmarshal(data, &argument)
+	baseDir := "."
+	if filePath != StdinPath {
+		baseDir = filepath.Dir(filePath)
+	}
+	if err := ResolveRefs(arguments, filePath, baseDir); err != nil {
+		return nil, err
+	}
+	return arguments, nil
+}
+
+func fileFormat(filePath string, data []byte) Format {
+	if format, ok := FormatFromPath(filePath); ok {
+		return format
+	}
+	return DetectFormat(data)
+}
+
+// LoadFrom reads an argument from r, such as standard input, an unsaved
+// editor buffer or an HTTP request body.
+func LoadFrom(r io.Reader) (*Argument, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
+	return LoadBytes(data)
+}
 
-	return &argument, err
+// LoadBytes parses an argument from data in the sniffed format. Refs are
+// left for ResolveRefs.
+func LoadBytes(data []byte) (*Argument, error) {
+	return DecodeArgument(data, DetectFormat(data))
 }
@@ -0,0 +1,47 @@
+package ctac
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadFrom(t *testing.T) {
+
+	input := `title: "Piped"
+premises:
+-   id: P1
+    text: "Arguments can be piped"
+conclusion:
+    text: "We can lint unsaved buffers"
+`
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "A valid argument is loaded", input: input},
+		{name: "Invalid YAML is an error", input: "title: [", wantErr: true},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			fromReader, err := LoadFrom(strings.NewReader(tc.input))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loading: %v", err)
+			}
+			fromBytes, err := LoadBytes([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("loading bytes: %v", err)
+			}
+			if fromReader.Title != "Piped" || len(fromReader.Premises) != 1 || fromBytes.Conclusion.Text != fromReader.Conclusion.Text {
+				t.Fatalf("got %+v and %+v but we wanted the same argument", fromReader, fromBytes)
+			}
+		})
+	}
+}
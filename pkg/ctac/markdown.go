@@ -0,0 +1,85 @@
+package ctac
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errNoMarkdownArgument = errors.New("markdown has no ```ctac block or YAML front matter with an argument")
+
+var regexCtacFence = regexp.MustCompile("(?m)^(```|~~~)ctac[ \t]*\r?$")
+
+// DecodeMarkdown reads an argument from a Markdown decision record, taken
+// from its first fenced ```ctac block or, when there is none, from its YAML
+// front matter. A ```ctac block may hold YAML, JSON or TOML. Lines are
+// those of the Markdown document, so issues point at the record itself.
+func DecodeMarkdown(data []byte) (*Argument, error) {
+	return DecodeArgument(data, FormatMarkdown)
+}
+
+// decodeMarkdown reads the arguments of a Markdown document's ```ctac block
+// or front matter.
+func decodeMarkdown(data []byte) ([]Argument, error) {
+	block, offset, err := markdownArgument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	format := DetectFormat(block)
+	if format == FormatMarkdown {
+		format = FormatYAML
+	}
+	arguments, err := decodeArguments(block, format, offset)
+	if err != nil {
+		return nil, fmt.Errorf("markdown line %d: %w", offset+1, err)
+	}
+	return arguments, nil
+}
+
+// markdownArgument returns the ```ctac block of a Markdown document or, when
+// there is none, its front matter, and the number of lines before it. Front
+// matter only counts when it has premises, a conclusion or arguments, so the
+// layout and status fields of ordinary ADR front matter are not mistaken for
+// an argument.
+func markdownArgument(data []byte) ([]byte, int, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+
+	for i, line := range lines {
+		if regexCtacFence.MatchString(strings.TrimRight(line, "\n")) {
+			return markdownBlock(lines, strings.TrimSpace(line)[:3], i+1)
+		}
+	}
+
+	if strings.TrimSpace(lines[0]) != "---" {
+		return nil, 0, errNoMarkdownArgument
+	}
+	block, start, err := markdownBlock(lines, "---", 1)
+	if err != nil {
+		return nil, 0, err
+	}
+	var fields map[string]any
+	if yaml.Unmarshal(block, &fields) == nil {
+		_, premises := fields["premises"]
+		_, conclusion := fields["conclusion"]
+		_, arguments := fields["arguments"]
+		if !premises && !conclusion && !arguments {
+			return nil, 0, errNoMarkdownArgument
+		}
+	}
+	return block, start, nil
+}
+
+// markdownBlock returns the lines from start up to the closing fence.
+func markdownBlock(lines []string, fence string, start int) ([]byte, int, error) {
+	for i := start; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if closing == fence || (fence == "---" && closing == "...") {
+			return []byte(strings.Join(lines[start:i], "")), start, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("markdown line %d: unterminated %s block", start, fence)
+}
@@ -0,0 +1,135 @@
+package ctac
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const frontMatterADR = `---
+title: "Adopt Postgres"
+premises:
+-   id: P1
+    text: "Our data is relational"
+    confidence: high
+-   id: P2
+    text: "The outage was catastrophic"
+    confidence: high
+conclusion:
+    text: "We should adopt Postgres"
+    modality: should
+---
+# ADR 7: Adopt Postgres
+
+Some prose.
+`
+
+const fencedADR = "# ADR 8: Cache responses\n" +
+	"\n" +
+	"## Context\n" +
+	"\n" +
+	"```ctac\n" +
+	"title: \"Cache responses\"\n" +
+	"premises:\n" +
+	"-   id: P1\n" +
+	"    text: \"Latency is terrible\"\n" +
+	"    confidence: high\n" +
+	"```\n"
+
+// ADR front matter with a ```ctac block: the block holds the argument.
+const frontMatterAndFencedADR = "---\n" +
+	"layout: adr\n" +
+	"status: accepted\n" +
+	"title: ADR 9\n" +
+	"---\n" +
+	"# ADR 9: Queue emails\n" +
+	"\n" +
+	"```ctac\n" +
+	"title: \"Queue emails\"\n" +
+	"premises:\n" +
+	"-   id: P1\n" +
+	"    text: \"Sending blocks the request for 2 seconds\"\n" +
+	"    confidence: high\n" +
+	"-   id: P2\n" +
+	"    text: \"The queue is already running\"\n" +
+	"    confidence: high\n" +
+	"conclusion:\n" +
+	"    text: \"We should queue emails\"\n" +
+	"    modality: should\n" +
+	"```\n"
+
+func TestDecodeMarkdown(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		input        string
+		wantTitle    string
+		wantPremises int
+		wantLines    map[string]int
+		wantErr      string
+	}{
+		{
+			name:      "Front matter",
+			input:     frontMatterADR,
+			wantTitle: "Adopt Postgres",
+			wantLines: map[string]int{"CTAC007_EMOTIONAL_LANGUAGE_DETECTED": 7},
+		},
+		{
+			name:      "Fenced ctac block",
+			input:     fencedADR,
+			wantTitle: "Cache responses",
+			wantLines: map[string]int{"CTAC007_EMOTIONAL_LANGUAGE_DETECTED": 8, "CTAC003_MISSING_CONCLUSION_RULE": 6},
+		},
+		{
+			name:         "Fenced ctac block wins over front matter",
+			input:        frontMatterAndFencedADR,
+			wantTitle:    "Queue emails",
+			wantPremises: 2,
+		},
+		{name: "No argument", input: "# Just prose\n", wantErr: "no ```ctac block"},
+		{name: "Front matter without an argument", input: "---\nlayout: adr\nstatus: accepted\n---\n# ADR 10\n", wantErr: "no ```ctac block"},
+		{name: "Unterminated block", input: "```ctac\ntitle: x\n", wantErr: "unterminated"},
+		{name: "Validation errors carry the block line", input: "---\npremises:\n-   text: x\n---\n", wantErr: "markdown line 2: premises[0]: id is required"},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wantErr == "" && DetectFormat([]byte(tc.input)) != FormatMarkdown && !strings.HasPrefix(tc.input, "---") {
+				t.Fatalf("expected the document to be sniffed as Markdown")
+			}
+			argument, err := DecodeArgument([]byte(tc.input), FormatMarkdown)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decoding: %v", err)
+			}
+			if argument.Title != tc.wantTitle {
+				t.Fatalf("got title %q but we wanted %q", argument.Title, tc.wantTitle)
+			}
+			if tc.wantPremises > 0 && len(argument.Premises) != tc.wantPremises {
+				t.Fatalf("got %d premises but we wanted %d", len(argument.Premises), tc.wantPremises)
+			}
+
+			report := NewAnalyzer().Analyze(context.Background(), *argument)
+			for ruleID, wantLine := range tc.wantLines {
+				found := false
+				for _, issue := range report.Issues {
+					if issue.RuleID != ruleID {
+						continue
+					}
+					found = true
+					if issue.Line != wantLine {
+						t.Fatalf("%s: got line %d but we wanted %d", ruleID, issue.Line, wantLine)
+					}
+				}
+				if !found {
+					t.Fatalf("expected an issue from %s, got %v", ruleID, report.Issues)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package ctac
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Matrix is the weighted decision matrix of an argument: its alternatives
+// scored on its criteria, ranked by weighted total.
+type Matrix struct {
+	Criteria []Criterion
+	// Options are the alternatives from best to worst.
+	Options []MatrixOption
+	// Sensitivity holds, for each criterion in order, the smallest weight
+	// change that changes the winner.
+	Sensitivity []Sensitivity
+}
+
+// MatrixOption is an alternative in a decision matrix. Scores follow the
+// order of the criteria; options with the same total share a rank.
+type MatrixOption struct {
+	Name   string
+	Scores []float64
+	Total  float64
+	Rank   int
+}
+
+// Sensitivity is how much the weight of a criterion has to change for
+// another option to catch up with the winner. At Weight+Change the
+// Challenger ties with the winner; beyond it the Challenger wins. When no
+// change of weight can flip the winner, Challenger is empty.
+type Sensitivity struct {
+	Criterion  string
+	Weight     float64
+	Change     float64
+	Challenger string
+}
+
+func (s Sensitivity) String() string {
+	if s.Challenger == "" {
+		return fmt.Sprintf("No change to the weight of %s changes the winner", s.Criterion)
+	}
+	verb := "Raising"
+	if s.Change < 0 {
+		verb = "Lowering"
+	}
+	return fmt.Sprintf("%s the weight of %s from %s to %s (%+g) lets %s catch up", verb, s.Criterion, formatNumber(s.Weight), formatNumber(s.Weight+s.Change), roundNumber(s.Change), s.Challenger)
+}
+
+var (
+	errNoCriteria     = errors.New("argument has no criteria")
+	errNoAlternatives = errors.New("argument has no alternatives")
+)
+
+// NewMatrix builds the decision matrix of an argument. The argument needs
+// criteria and alternatives; scores that are missing count as zero.
+func NewMatrix(argument Argument) (*Matrix, error) {
+	if len(argument.Criteria) == 0 {
+		return nil, errNoCriteria
+	}
+	if len(argument.Alternatives) == 0 {
+		return nil, errNoAlternatives
+	}
+
+	matrix := &Matrix{Criteria: argument.Criteria}
+	for _, alternative := range argument.Alternatives {
+		option := MatrixOption{Name: alternative.Name, Scores: make([]float64, len(argument.Criteria))}
+		for i, criterion := range argument.Criteria {
+			option.Scores[i] = alternative.Scores[criterion.Name]
+			option.Total += criterion.Weight * option.Scores[i]
+		}
+		matrix.Options = append(matrix.Options, option)
+	}
+
+	sort.SliceStable(matrix.Options, func(i, j int) bool {
+		return matrix.Options[i].Total > matrix.Options[j].Total
+	})
+	for i := range matrix.Options {
+		matrix.Options[i].Rank = i + 1
+		if i > 0 && matrix.Options[i].Total == matrix.Options[i-1].Total {
+			matrix.Options[i].Rank = matrix.Options[i-1].Rank
+		}
+	}
+
+	winner := matrix.Options[0]
+	for i, criterion := range argument.Criteria {
+		sensitivity := Sensitivity{Criterion: criterion.Name, Weight: criterion.Weight}
+		for _, challenger := range matrix.Options[1:] {
+			// The totals are linear in the weight, so the challenger ties
+			// with the winner after a change of gap / (score difference).
+			difference := challenger.Scores[i] - winner.Scores[i]
+			if difference == 0 {
+				continue
+			}
+			change := (winner.Total - challenger.Total) / difference
+			if criterion.Weight+change < 0 {
+				continue
+			}
+			if sensitivity.Challenger == "" || math.Abs(change) < math.Abs(sensitivity.Change) {
+				sensitivity.Change = change
+				sensitivity.Challenger = challenger.Name
+			}
+		}
+		matrix.Sensitivity = append(matrix.Sensitivity, sensitivity)
+	}
+	return matrix, nil
+}
+
+// Top returns the options ranked first; there are several on a tie.
+func (m *Matrix) Top() []MatrixOption {
+	var top []MatrixOption
+	for _, option := range m.Options {
+		if option.Rank == 1 {
+			top = append(top, option)
+		}
+	}
+	return top
+}
+
+// roundNumber rounds to two decimals, hiding floating point noise such as
+// 0.30000000000000004.
+func roundNumber(x float64) float64 {
+	return math.Round(x*100) / 100
+}
+
+func formatNumber(x float64) string {
+	return strconv.FormatFloat(roundNumber(x), 'f', -1, 64)
+}
+
+// FormatMatrix renders a decision matrix as a table followed by its
+// sensitivity.
+func FormatMatrix(m *Matrix) string {
+	header := []string{"Rank", "Option"}
+	for _, criterion := range m.Criteria {
+		header = append(header, fmt.Sprintf("%s (×%s)", criterion.Name, formatNumber(criterion.Weight)))
+	}
+	header = append(header, "Total")
+
+	rows := [][]string{header}
+	for _, option := range m.Options {
+		row := []string{strconv.Itoa(option.Rank), option.Name}
+		for _, score := range option.Scores {
+			row = append(row, formatNumber(score))
+		}
+		rows = append(rows, append(row, formatNumber(option.Total)))
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+
+	var b strings.Builder
+	for r, row := range rows {
+		b.WriteString("|")
+		for i, cell := range row {
+			fmt.Fprintf(&b, " %s%s |", cell, strings.Repeat(" ", widths[i]-len([]rune(cell))))
+		}
+		b.WriteString("\n")
+		if r == 0 {
+			b.WriteString("|")
+			for _, width := range widths {
+				fmt.Fprintf(&b, "%s|", strings.Repeat("-", width+2))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\nSensitivity:\n")
+	for _, sensitivity := range m.Sensitivity {
+		fmt.Fprintf(&b, "- %s\n", sensitivity)
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package ctac
+
+import (
+	"strings"
+	"testing"
+)
+
+func databaseDecision() Argument {
+	return Argument{
+		Title: "Choose a database",
+		Criteria: []Criterion{
+			{Name: "Cost", Weight: 3},
+			{Name: "Scalability", Weight: 2},
+			{Name: "Team familiarity", Weight: 1},
+		},
+		Alternatives: []Alternative{
+			{Name: "PostgreSQL", Scores: map[string]float64{"Cost": 4, "Scalability": 3, "Team familiarity": 5}},
+			{Name: "CockroachDB", Scores: map[string]float64{"Cost": 2, "Scalability": 5, "Team familiarity": 2}},
+			{Name: "DynamoDB", Scores: map[string]float64{"Cost": 3, "Scalability": 5, "Team familiarity": 1}},
+		},
+		Conclusion: Conclusion{Text: "We should stay on PostgreSQL", Modality: ModalityShould, Confidence: Medium},
+	}
+}
+
+func TestNewMatrix(t *testing.T) {
+
+	matrix, err := NewMatrix(databaseDecision())
+	if err != nil {
+		t.Fatalf("NewMatrix: %v", err)
+	}
+
+	var ranking []string
+	for _, option := range matrix.Options {
+		ranking = append(ranking, option.Name+"="+formatNumber(option.Total))
+	}
+	if got, want := strings.Join(ranking, " "), "PostgreSQL=23 DynamoDB=20 CockroachDB=18"; got != want {
+		t.Errorf("got ranking %s, want %s", got, want)
+	}
+
+	want := []Sensitivity{
+		{Criterion: "Cost", Weight: 3, Change: -2.5, Challenger: "CockroachDB"},
+		{Criterion: "Scalability", Weight: 2, Change: 1.5, Challenger: "DynamoDB"},
+		{Criterion: "Team familiarity", Weight: 1, Change: -0.75, Challenger: "DynamoDB"},
+	}
+	for i, w := range want {
+		if got := matrix.Sensitivity[i]; got != w {
+			t.Errorf("sensitivity of %s: got %+v, want %+v", w.Criterion, got, w)
+		}
+	}
+}
+
+func TestMatrixTiesAndLimits(t *testing.T) {
+
+	argument := Argument{
+		Criteria: []Criterion{{Name: "Cost", Weight: 1}, {Name: "Speed", Weight: 1}},
+		Alternatives: []Alternative{
+			{Name: "A", Scores: map[string]float64{"Cost": 5, "Speed": 5}},
+			{Name: "B", Scores: map[string]float64{"Cost": 3, "Speed": 3}},
+			{Name: "C", Scores: map[string]float64{"Cost": 5, "Speed": 5}},
+		},
+	}
+	matrix, err := NewMatrix(argument)
+	if err != nil {
+		t.Fatalf("NewMatrix: %v", err)
+	}
+	if top := matrix.Top(); len(top) != 2 || top[0].Name != "A" || top[1].Name != "C" {
+		t.Errorf("got top options %+v, want A and C", top)
+	}
+	if rank := matrix.Options[2].Rank; rank != 3 {
+		t.Errorf("got rank %d for B, want 3", rank)
+	}
+	// B scores lower on every criterion, so no weight can make it win, and
+	// C scores the same as A.
+	for _, sensitivity := range matrix.Sensitivity {
+		if sensitivity.Challenger != "" {
+			t.Errorf("got %+v, want no challenger", sensitivity)
+		}
+	}
+
+	if _, err := NewMatrix(Argument{Alternatives: argument.Alternatives}); err == nil {
+		t.Errorf("got no error for an argument without criteria")
+	}
+	if _, err := NewMatrix(Argument{Criteria: argument.Criteria}); err == nil {
+		t.Errorf("got no error for an argument without alternatives")
+	}
+}
+
+func TestTopOptionRule(t *testing.T) {
+
+	rule := TopOptionRule{}
+	other := databaseDecision()
+	other.Conclusion.Text = "We should move to CockroachDB"
+	unnamed := databaseDecision()
+	unnamed.Conclusion.Text = "We should keep things as they are"
+	noMatrix := databaseDecision()
+	noMatrix.Criteria = nil
+	languages := Argument{
+		Title:    "Choose a language",
+		Criteria: []Criterion{{Name: "Speed", Weight: 1}},
+		Alternatives: []Alternative{
+			{Name: "Go", Scores: map[string]float64{"Speed": 5}},
+			{Name: "Java", Scores: map[string]float64{"Speed": 3}},
+			{Name: "Rust", Scores: map[string]float64{"Speed": 1}},
+		},
+		Conclusion: Conclusion{Text: "We should go with Rust", Modality: ModalityShould, Confidence: Medium},
+	}
+
+	cases := TestCases{
+		{name: "Conclusion names the top option", argument: databaseDecision(), wantIssues: 0},
+		{name: "Conclusion names another option", argument: other, wantIssues: 1},
+		{name: "Conclusion names no option", argument: unnamed, wantIssues: 1},
+		{name: "No decision matrix", argument: noMatrix, wantIssues: 0},
+		{name: "Option names match whole words only", argument: languages, wantIssues: 1},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := rule.Check(tc.argument)
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing argument %q: got %d issue%s but we wanted %d", tc.argument.Title, got, plural(got), tc.wantIssues)
+			}
+		})
+	}
+}
+
+func TestCriteriaValidation(t *testing.T) {
+
+	data := `title: Choose a database
+premises:
+  - id: P1
+    text: The database is at 80% capacity
+    confidence: high
+criteria:
+  - name: Cost
+    weight: 3
+  - name: Cost
+    weight: 0
+alternatives:
+  - name: PostgreSQL
+    scores: {Cost: 4, Speed: 2}
+  - name: DynamoDB
+conclusion:
+  text: We should stay on PostgreSQL
+  modality: should
+  confidence: medium
+`
+	_, err := DecodeArgument([]byte(data), FormatYAML)
+	want := []string{
+		`criteria[1]: duplicate name "Cost"`,
+		"criteria[1]: weight must be positive, got 0",
+		`alternatives[0].scores: unknown criterion "Speed"`,
+		`alternatives[1].scores: no score for criterion "Cost"`,
+	}
+	for _, w := range want {
+		if err == nil || !strings.Contains(err.Error(), w) {
+			t.Errorf("got error %v, want it to contain %q", err, w)
+		}
+	}
+
+	valid := strings.NewReplacer("  - name: Cost\n    weight: 0\n", "", ", Speed: 2", "", "  - name: DynamoDB\n", "").Replace(data)
+	argument, err := DecodeArgument([]byte(valid), FormatYAML)
+	if err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if line := argument.Criteria[0].Line; line != 7 {
+		t.Errorf("got criterion line %d, want 7", line)
+	}
+	for _, format := range Formats {
+		encoded, err := EncodeArgument(argument, format)
+		if err != nil {
+			t.Fatalf("%s: encoding: %v", format, err)
+		}
+		decoded, err := DecodeArgument(encoded, format)
+		if err != nil {
+			t.Fatalf("%s: decoding: %v", format, err)
+		}
+		if decoded.Criteria[0].Weight != 3 || decoded.Alternatives[0].Scores["Cost"] != 4 {
+			t.Errorf("%s: the matrix did not round-trip: %+v %+v", format, decoded.Criteria, decoded.Alternatives)
+		}
+	}
+}
@@ -5,7 +5,7 @@ type Modality string
 const (
 	ModalityMust   Modality = "must"
 	ModalityShould Modality = "should"
-	ModalityCould Modality = "could"
+	ModalityCould  Modality = "could"
 )
 
 type Confidence string
@@ -16,20 +16,102 @@ const (
 	High   Confidence = "high"
 )
 
+// Status is where a decision record stands.
+type Status string
+
+const (
+	StatusProposed   Status = "proposed"
+	StatusAccepted   Status = "accepted"
+	StatusSuperseded Status = "superseded"
+	StatusRejected   Status = "rejected"
+)
+
+// Metadata describes the decision an argument records. Its fields sit at
+// the top level of the argument file.
+type Metadata struct {
+	Status    Status   `yaml:"status,omitempty" json:"status,omitempty" toml:"status,omitempty"`
+	Authors   []string `yaml:"authors,omitempty" json:"authors,omitempty" toml:"authors,omitempty"`
+	DecidedAt Date     `yaml:"decidedAt,omitempty" json:"decidedAt,omitzero" toml:"decidedAt,omitempty"`
+	Reviewers []string `yaml:"reviewers,omitempty" json:"reviewers,omitempty" toml:"reviewers,omitempty"`
+	Tags      []string `yaml:"tags,omitempty" json:"tags,omitempty" toml:"tags,omitempty"`
+	// Supersedes links to the records this one replaces, such as
+	// "adr/0003-queue.yaml".
+	Supersedes []string `yaml:"supersedes,omitempty" json:"supersedes,omitempty" toml:"supersedes,omitempty"`
+}
+
 type Argument struct {
-	Title      string     `yaml:"title"`
-	Premises   []Premise  `yaml:"premises"`
-	Conclusion Conclusion `yaml:"conclusion"`
+	Title    string `yaml:"title" json:"title" toml:"title"`
+	Metadata `yaml:",inline"`
+	// Language is an ISO 639-1 code selecting the lexicons used by the
+	// rules. When empty it is detected from the text.
+	Language   string     `yaml:"language,omitempty" json:"language,omitempty" toml:"language,omitempty"`
+	Premises   []Premise  `yaml:"premises" json:"premises" toml:"premises"`
+	Conclusion Conclusion `yaml:"conclusion" json:"conclusion" toml:"conclusion"`
+	// Alternatives are the options that were considered. With Criteria they
+	// form a decision matrix, and may include the option chosen.
+	Alternatives []Alternative `yaml:"alternatives,omitempty" json:"alternatives,omitempty" toml:"alternatives,omitempty"`
+	// Criteria are the weighted criteria the alternatives are scored on.
+	Criteria []Criterion `yaml:"criteria,omitempty" json:"criteria,omitempty" toml:"criteria,omitempty"`
+	// Assumptions are the IDs of registry assumptions the whole argument
+	// relies on; premises can cite their own.
+	Assumptions []string `yaml:"assumptions,omitempty" json:"assumptions,omitempty" toml:"assumptions,omitempty"`
+	// Line is where the argument starts in its file, and the Line of
+	// premises and the conclusion where they start. They are set by the
+	// loader for YAML and Markdown input and are 0 otherwise.
+	Line int `yaml:"-" json:"-" toml:"-"`
+	// Index is the position of the argument in a file holding several.
+	Index int `yaml:"-" json:"-" toml:"-"`
 }
 
 type Premise struct {
-	Id         string     `yaml:"id"`
-	Text       string     `yaml:"text"`
-	Confidence Confidence `yaml:"confidence"`
+	Id          string     `yaml:"id" json:"id" toml:"id"`
+	Text        string     `yaml:"text" json:"text" toml:"text"`
+	Confidence  Confidence `yaml:"confidence" json:"confidence" toml:"confidence"`
+	Sources     []Source   `yaml:"sources,omitempty" json:"sources,omitempty" toml:"sources,omitempty"`
+	Assumptions []string   `yaml:"assumptions,omitempty" json:"assumptions,omitempty" toml:"assumptions,omitempty"`
+	// AsOf is when the premise was last known to hold, and Expires when it
+	// stops being trustworthy.
+	AsOf    Date `yaml:"asOf,omitempty" json:"asOf,omitzero" toml:"asOf,omitempty"`
+	Expires Date `yaml:"expires,omitempty" json:"expires,omitzero" toml:"expires,omitempty"`
+	// Ref points to a shared premise, as file#ID; see ResolveRefs.
+	Ref  string `yaml:"ref,omitempty" json:"ref,omitempty" toml:"ref,omitempty"`
+	Line int    `yaml:"-" json:"-" toml:"-"`
+}
+
+// Source is the evidence cited for a premise. Quote holds the words taken
+// verbatim from the source, which are not attributed to the author.
+type Source struct {
+	Title string `yaml:"title" json:"title" toml:"title"`
+	URL   string `yaml:"url,omitempty" json:"url,omitempty" toml:"url,omitempty"`
+	Quote string `yaml:"quote,omitempty" json:"quote,omitempty" toml:"quote,omitempty"`
+	// AsOf is when the source was published or last checked.
+	AsOf    Date `yaml:"asOf,omitempty" json:"asOf,omitzero" toml:"asOf,omitempty"`
+	Expires Date `yaml:"expires,omitempty" json:"expires,omitzero" toml:"expires,omitempty"`
+}
+
+// Alternative is an option that was considered for the decision. Pros and
+// cons are written like premises; their IDs are optional.
+type Alternative struct {
+	Name            string    `yaml:"name" json:"name" toml:"name"`
+	Pros            []Premise `yaml:"pros,omitempty" json:"pros,omitempty" toml:"pros,omitempty"`
+	Cons            []Premise `yaml:"cons,omitempty" json:"cons,omitempty" toml:"cons,omitempty"`
+	RejectedBecause string    `yaml:"rejectedBecause,omitempty" json:"rejectedBecause,omitempty" toml:"rejectedBecause,omitempty"`
+	// Scores maps the name of each criterion to the alternative's score.
+	Scores map[string]float64 `yaml:"scores,omitempty" json:"scores,omitempty" toml:"scores,omitempty"`
+	Line   int                `yaml:"-" json:"-" toml:"-"`
+}
+
+// Criterion is something the alternatives of a decision are scored on.
+// Scores are multiplied by its weight.
+type Criterion struct {
+	Name   string  `yaml:"name" json:"name" toml:"name"`
+	Weight float64 `yaml:"weight" json:"weight" toml:"weight"`
+	Line   int     `yaml:"-" json:"-" toml:"-"`
 }
 
 type Conclusion struct {
-	Text       string     `yaml:"text"`
-	Modality   Modality   `yaml:"modality"`
-	Confidence Confidence `yaml:"confidence"`
+	Text       string     `yaml:"text" json:"text" toml:"text"`
+	Modality   Modality   `yaml:"modality" json:"modality" toml:"modality"`
+	Confidence Confidence `yaml:"confidence" json:"confidence" toml:"confidence"`
+	Line       int        `yaml:"-" json:"-" toml:"-"`
 }
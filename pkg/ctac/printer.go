@@ -2,19 +2,78 @@ package ctac
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 )
 
 func SummariseArgument(argument Argument) string {
 
-	summaryArgument := fmt.Sprintf("Title: %s\nPremises: %d\n", argument.Title, len(argument.Premises))
+	summaryArgument := fmt.Sprintf("Title: %s\n", argument.Title)
+	summaryArgument += summariseMetadata(argument.Metadata)
+	summaryArgument += fmt.Sprintf("Premises: %d\n", len(argument.Premises))
 	for i, p := range argument.Premises {
 		summaryArgument += fmt.Sprintf("P%d. %s | Confidence: %s\n", i+1, p.Text, p.Confidence)
 	}
 
-	summaryArgument += fmt.Sprintf("--------------\nConclusion: %s | Confidence: %s\n", argument.Conclusion.Text, argument.Conclusion.Confidence)
+	if len(argument.Alternatives) > 0 {
+		summaryArgument += fmt.Sprintf("Alternatives: %d\n", len(argument.Alternatives))
+		for _, alternative := range argument.Alternatives {
+			summaryArgument += fmt.Sprintf("- %s | Pros: %d | Cons: %d", alternative.Name, len(alternative.Pros), len(alternative.Cons))
+			if alternative.RejectedBecause != "" {
+				summaryArgument += fmt.Sprintf(" | Rejected because: %s", alternative.RejectedBecause)
+			}
+			summaryArgument += "\n"
+		}
+	}
+
+	if len(argument.Criteria) > 0 {
+		criteria := make([]string, 0, len(argument.Criteria))
+		for _, criterion := range argument.Criteria {
+			criteria = append(criteria, fmt.Sprintf("%s (×%s)", criterion.Name, formatNumber(criterion.Weight)))
+		}
+		summaryArgument += fmt.Sprintf("Criteria: %s\n", strings.Join(criteria, ", "))
+	}
+
+	summaryArgument += fmt.Sprintf("--------------\nConclusion: %s | Modality: %s | Confidence: %s\n", argument.Conclusion.Text, argument.Conclusion.Modality, argument.Conclusion.Confidence)
 	return summaryArgument
 }
 
+// summariseMetadata lists the metadata that is set, one field per line.
+func summariseMetadata(metadata Metadata) string {
+	var summary string
+	for _, field := range metadataFields(metadata) {
+		summary += fmt.Sprintf("%s: %s\n", field.Name, field.Value)
+	}
+	return summary
+}
+
+// metadataField is a metadata field that is set, ready to print.
+type metadataField struct {
+	Name  string
+	Value string
+}
+
+// metadataFields returns the metadata that is set, in a fixed order.
+func metadataFields(metadata Metadata) []metadataField {
+	var fields []metadataField
+	if metadata.Status != "" {
+		fields = append(fields, metadataField{"Status", string(metadata.Status)})
+	}
+	if !metadata.DecidedAt.IsZero() {
+		fields = append(fields, metadataField{"Decided at", metadata.DecidedAt.String()})
+	}
+	for _, list := range []struct {
+		name    string
+		entries []string
+	}{{"Authors", metadata.Authors}, {"Reviewers", metadata.Reviewers}, {"Tags", metadata.Tags}, {"Supersedes", metadata.Supersedes}} {
+		if len(list.entries) > 0 {
+			fields = append(fields, metadataField{list.name, strings.Join(list.entries, ", ")})
+		}
+	}
+	return fields
+}
+
 func plural(n int) string {
 	if n == 1 {
 		return ""
@@ -22,16 +81,187 @@ func plural(n int) string {
 	return "s"
 }
 
-func FormatIssueMessage(issues []Issue) string {
+// ANSI escape codes used by the text report.
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiUnderline = "\033[4m"
+	ansiRed       = "\033[31m"
+	ansiYellow    = "\033[33m"
+	ansiCyan      = "\033[36m"
+	ansiGreen     = "\033[32m"
+)
+
+// ColorEnabled reports whether a report written to f should be colored:
+// f is a terminal and neither NO_COLOR is set nor TERM is dumb.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// textStyle applies ANSI styles when color is on.
+type textStyle bool
+
+func (color textStyle) apply(text string, codes ...string) string {
+	if !color || text == "" {
+		return text
+	}
+	return strings.Join(codes, "") + text + ansiReset
+}
+
+func (color textStyle) severity(severity Severity) string {
+	label := fmt.Sprintf("%-7s", severity)
+	switch severity {
+	case SeverityError:
+		return color.apply(label, ansiBold, ansiRed)
+	case SeverityWarning:
+		return color.apply(label, ansiYellow)
+	default:
+		return color.apply(label, ansiCyan)
+	}
+}
+
+// highlight marks the words in text, bold and underlined with color or in
+// brackets without. Words match whole words, ignoring case.
+func (color textStyle) highlight(text string, words []string) string {
+	var spans [][]int
+	for _, word := range words {
+		phrases, err := buildPhrases([]LexiconEntry{{Term: word}})
+		if err != nil || word == "" {
+			continue
+		}
+		spans = append(spans, phrases[0].locate(text)...)
+	}
+	if len(spans) == 0 {
+		return text
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var b strings.Builder
+	end := 0
+	for _, span := range spans {
+		if span[0] < end {
+			// Overlaps a word already marked, such as "some" in "some people".
+			continue
+		}
+		b.WriteString(text[end:span[0]])
+		if color {
+			b.WriteString(color.apply(text[span[0]:span[1]], ansiBold, ansiUnderline))
+		} else {
+			b.WriteString("[" + text[span[0]:span[1]] + "]")
+		}
+		end = span[1]
+	}
+	b.WriteString(text[end:])
+	return b.String()
+}
+
+// issueGroup is the issues about one premise, the conclusion or the
+// argument as a whole.
+type issueGroup struct {
+	label  string
+	text   string
+	line   int
+	issues []Issue
+}
+
+// groupIssues groups issues by premise, in the order of the premises,
+// followed by the conclusion and the argument. Issues are attributed to the
+// conclusion by their line, so only when it is known.
+func groupIssues(argument Argument, issues []Issue) []*issueGroup {
+	var groups []*issueGroup
+	byPremise := make(map[string]*issueGroup)
+	for _, p := range argument.Premises {
+		group := &issueGroup{label: p.Id, text: p.Text, line: p.Line}
+		byPremise[p.Id] = group
+		groups = append(groups, group)
+	}
+	conclusion := &issueGroup{label: "Conclusion", text: argument.Conclusion.Text, line: argument.Conclusion.Line}
+	whole := &issueGroup{label: "Argument"}
+
+	for _, issue := range issues {
+		switch {
+		case issue.Premise != "":
+			group, ok := byPremise[issue.Premise]
+			if !ok {
+				group = &issueGroup{label: issue.Premise, line: issue.Line}
+				byPremise[issue.Premise] = group
+				groups = append(groups, group)
+			}
+			group.issues = append(group.issues, issue)
+		case issue.Line > 0 && issue.Line == argument.Conclusion.Line:
+			conclusion.issues = append(conclusion.issues, issue)
+		default:
+			whole.issues = append(whole.issues, issue)
+		}
+	}
+
+	groups = append(groups, conclusion, whole)
+	kept := groups[:0]
+	for _, group := range groups {
+		if len(group.issues) > 0 {
+			sort.SliceStable(group.issues, func(i, j int) bool {
+				return group.issues[i].Severity.rank() > group.issues[j].Severity.rank()
+			})
+			kept = append(kept, group)
+		}
+	}
+	return kept
+}
+
+// FormatIssueMessage renders the issues of a report grouped by premise,
+// with the words that triggered them highlighted and their hints, followed
+// by the score and a count per severity. Color adds ANSI colors; see
+// ColorEnabled.
+func FormatIssueMessage(argument Argument, report Report, color bool) string {
+	style := textStyle(color)
+	issues := report.Issues
+	formattedScore := fmt.Sprintf("Score: %s\n", style.apply(fmt.Sprintf("%d/100 (%s)", report.Score, Grade(report.Score)), ansiBold))
 
 	if len(issues) == 0 {
-		return "✅ No issues found.\n"
+		return style.apply("✅ No issues found.", ansiGreen) + "\n" + formattedScore
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d issue%s:\n", len(issues), plural(len(issues)))
+	for _, group := range groupIssues(argument, issues) {
+		b.WriteString("\n" + style.apply(group.label, ansiBold))
+		if group.line > 0 {
+			b.WriteString(style.apply(fmt.Sprintf(" (line %d)", group.line), ansiDim))
+		}
+		if group.text != "" {
+			var words []string
+			for _, issue := range group.issues {
+				words = append(words, issue.Words...)
+			}
+			b.WriteString("  " + style.highlight(group.text, words))
+		}
+		b.WriteString("\n")
+
+		for _, issue := range group.issues {
+			fmt.Fprintf(&b, "  %s  %s  %s", style.severity(issue.Severity), style.apply(issue.RuleID, ansiDim), issue.Message)
+			if group.line == 0 && issue.Line > 0 {
+				fmt.Fprintf(&b, " %s", style.apply(fmt.Sprintf("(line %d)", issue.Line), ansiDim))
+			}
+			b.WriteString("\n")
+			if issue.Hint != "" {
+				fmt.Fprintf(&b, "           %s %s\n", style.apply("hint:", ansiDim), issue.Hint)
+			}
+		}
 	}
-	var formattedIssues string
 
-	formattedIssues += fmt.Sprintf("Found %d issue%s:\n\n", len(issues), plural((len(issues))))
+	counts := make(map[Severity]int)
 	for _, issue := range issues {
-		formattedIssues += fmt.Sprintf("- %s |  %s | %s |\n", issue.RuleID, issue.Severity, issue.Message)
+		counts[issue.Severity]++
 	}
-	return formattedIssues
+	b.WriteString("\n" + formattedScore)
+	fmt.Fprintf(&b, "%s, %s, %s\n",
+		style.apply(fmt.Sprintf("%d error%s", counts[SeverityError], plural(counts[SeverityError])), ansiRed),
+		style.apply(fmt.Sprintf("%d warning%s", counts[SeverityWarning], plural(counts[SeverityWarning])), ansiYellow),
+		style.apply(fmt.Sprintf("%d info", counts[SeverityInfo]), ansiCyan))
+	return b.String()
 }
@@ -0,0 +1,85 @@
+package ctac
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatIssueMessage(t *testing.T) {
+
+	argument := Argument{
+		Title: "Move to microservices",
+		Premises: []Premise{
+			{Id: "P1", Text: "Deploys take 40 minutes", Confidence: High, Line: 3},
+			{Id: "P2", Text: "Some engineers say deploys are obviously too slow", Confidence: Low, Line: 6},
+		},
+		Conclusion: Conclusion{Text: "We must move to microservices", Modality: ModalityMust, Confidence: High, Line: 9},
+	}
+	report := NewAnalyzer().Analyze(t.Context(), argument)
+	got := FormatIssueMessage(argument, report, false)
+
+	want := []string{
+		"P2 (line 6)  [Some] engineers say deploys are [obviously] too slow\n",
+		"  error    CTAC007_EMOTIONAL_LANGUAGE_DETECTED  ",
+		"           hint: Remove use of vague words by using more precise language\n",
+		"Conclusion (line 9)  We must move to microservices\n",
+		"  warning  CTAC013_MUST_WITHOUT_ALTERNATIVES  ",
+		"1 error, 2 warnings, 0 info\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("got report\n%s\nwant it to contain %q", got, w)
+		}
+	}
+	if strings.Contains(got, "\033[") {
+		t.Errorf("got ANSI codes in an uncolored report:\n%s", got)
+	}
+	if strings.Contains(got, "\nP1") {
+		t.Errorf("got a group for P1, which has no issues:\n%s", got)
+	}
+	// Errors are listed before warnings within a premise.
+	if strings.Index(got, "CTAC007") > strings.Index(got, "CTAC002") {
+		t.Errorf("got warnings before errors:\n%s", got)
+	}
+
+	colored := FormatIssueMessage(argument, report, true)
+	if !strings.Contains(colored, ansiBold+ansiUnderline+"obviously"+ansiReset) {
+		t.Errorf("got colored report without highlighted words:\n%q", colored)
+	}
+
+	empty := FormatIssueMessage(argument, Report{Score: 95}, false)
+	if empty != "✅ No issues found.\nScore: 95/100 (A)\n" {
+		t.Errorf("got %q for a report without issues", empty)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "report.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if ColorEnabled(file) {
+		t.Errorf("got color enabled for a regular file")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(os.Stdout) {
+		t.Errorf("got color enabled with NO_COLOR set")
+	}
+}
+
+func TestSummariseArgument(t *testing.T) {
+
+	summary := SummariseArgument(Argument{
+		Title:      "Cache search responses",
+		Premises:   []Premise{{Id: "P1", Text: "Search is slow", Confidence: High}},
+		Conclusion: Conclusion{Text: "We should cache search responses", Modality: ModalityShould, Confidence: Medium},
+	})
+	if want := "Conclusion: We should cache search responses | Modality: should | Confidence: medium\n"; !strings.Contains(summary, want) {
+		t.Errorf("got summary\n%s\nwant it to contain %q", summary, want)
+	}
+}
@@ -0,0 +1,172 @@
+package ctac
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// External-process rules.
+//
+// A process rule runs a local executable for every argument. ctac writes the
+// argument as JSON to the process's stdin and reads issues from its stdout,
+// one JSON object per line (RuleID, Severity, Message, Hint). Blank lines
+// are ignored. The process must exit with status 0.
+
+const defaultProcessTimeout = 10 * time.Second
+
+// maxStderr bounds how much of a failing process's stderr is reported.
+const maxStderr = 512
+
+// maxProcessOutput bounds how much a process may write to stdout.
+const maxProcessOutput = 4 << 20
+
+// processWaitDelay is how long to wait for the output of a killed process,
+// whose children may still hold its stdout open.
+const processWaitDelay = 500 * time.Millisecond
+
+// ProcessRuleSpec declares a rule backed by a local executable.
+type ProcessRuleSpec struct {
+	ID      string        `yaml:"id" json:"id"`
+	Command string        `yaml:"command" json:"command"`
+	Args    []string      `yaml:"args" json:"args,omitempty"`
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// ProcessRule is a rule backed by a local executable.
+type ProcessRule struct {
+	id      string
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+// NewProcessRule returns the rule declared by spec. A command containing a
+// path separator is resolved relative to baseDir; other commands are looked
+// up in PATH when the rule runs.
+func NewProcessRule(spec ProcessRuleSpec, baseDir string) (*ProcessRule, error) {
+	if spec.ID == "" {
+		return nil, fmt.Errorf("process rule needs an id")
+	}
+	if spec.Command == "" {
+		return nil, fmt.Errorf("process rule %s: command is required", spec.ID)
+	}
+	command := spec.Command
+	if strings.ContainsRune(command, filepath.Separator) && !filepath.IsAbs(command) {
+		command = filepath.Join(baseDir, command)
+	}
+	timeout := spec.Timeout
+	if timeout <= 0 {
+		timeout = defaultProcessTimeout
+	}
+	return &ProcessRule{id: spec.ID, command: command, args: spec.Args, timeout: timeout}, nil
+}
+
+func (rule *ProcessRule) ID() string {
+	return rule.id
+}
+
+func (rule *ProcessRule) Check(argument Argument) []Issue {
+	issues, err := rule.CheckContext(context.Background(), argument)
+	if err != nil {
+		return []Issue{ruleErrorIssue(&RuleError{RuleID: rule.id, Err: err})}
+	}
+	return issues
+}
+
+// CheckContext runs the process against the argument, killing it when ctx
+// is done or the rule's timeout expires.
+func (rule *ProcessRule) CheckContext(ctx context.Context, argument Argument) ([]Issue, error) {
+	input, err := json.Marshal(argument)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rule.timeout)
+	defer cancel()
+
+	stdout := limitedBuffer{limit: maxProcessOutput, overflow: cancel}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, rule.command, rule.args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = processWaitDelay
+
+	err = cmd.Run()
+	if stdout.overflowed {
+		return nil, fmt.Errorf("process wrote more than %d bytes to stdout", maxProcessOutput)
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("process timed out after %s", rule.timeout)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if len(msg) > maxStderr {
+				msg = msg[len(msg)-maxStderr:]
+			}
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return decodeIssueLines(rule.id, stdout.Bytes())
+}
+
+// limitedBuffer keeps at most limit bytes of what is written to it and
+// calls overflow, once, when more are written. It is not a bytes.Buffer, so
+// io.Copy cannot bypass Write through ReadFrom.
+type limitedBuffer struct {
+	buf        bytes.Buffer
+	limit      int
+	overflow   func()
+	overflowed bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		if !b.overflowed {
+			b.overflowed = true
+			b.overflow()
+		}
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// decodeIssueLines parses issues written as one JSON object per line.
+func decodeIssueLines(ruleID string, output []byte) ([]Issue, error) {
+	var issues []Issue
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var issue Issue
+		if err := json.Unmarshal([]byte(text), &issue); err != nil {
+			return nil, fmt.Errorf("malformed output on line %d: %w", line, err)
+		}
+		issues = append(issues, issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading output: %w", err)
+	}
+	return validateExternalIssues(ruleID, issues)
+}
@@ -0,0 +1,107 @@
+package ctac
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessRule(t *testing.T) {
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	argument := Argument{Title: "Processes", Premises: []Premise{{Id: "P1", Text: "Processes are simple"}}}
+
+	cases := []struct {
+		name       string
+		script     string
+		wantIssues int
+		wantErr    string
+	}{
+		{
+			name:       "Issues are read as JSON lines",
+			script:     `cat >/dev/null; echo '{"Severity":"warning","Message":"first"}'; echo; echo '{"RuleID":"ACME002_OTHER","Message":"second"}'`,
+			wantIssues: 2,
+		},
+		{
+			name:       "The argument is written to stdin",
+			script:     `grep -q '"title":"Processes"' && echo '{"Message":"got the argument"}'`,
+			wantIssues: 1,
+		},
+		{name: "No output means no issues", script: `cat >/dev/null`, wantIssues: 0},
+		{name: "Non-zero exits are errors", script: `echo 'lexicon missing' >&2; exit 3`, wantErr: "lexicon missing"},
+		{name: "Malformed output is an error", script: `echo '{"Message":"ok"}'; echo 'oops'`, wantErr: "malformed output on line 2"},
+		{name: "Slow processes time out", script: `sleep 5`, wantErr: "timed out"},
+		{name: "Output is capped", script: `cat >/dev/null; yes`, wantErr: "more than 4194304 bytes"},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := NewProcessRule(ProcessRuleSpec{ID: "ACME002_PROCESS", Command: "sh", Args: []string{"-c", tc.script}, Timeout: 300 * time.Millisecond}, ".")
+			if err != nil {
+				t.Fatalf("creating rule: %v", err)
+			}
+
+			issues, err := rule.CheckContext(context.Background(), argument)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+				}
+				if checked := rule.Check(argument); len(checked) != 1 || checked[0].RuleID != "ACME002_PROCESS" || checked[0].Severity != SeverityError {
+					t.Fatalf("Check should report the failure as a single error issue, got %v", checked)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checking: %v", err)
+			}
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("got %d issue%s but we wanted %d", got, plural(got), tc.wantIssues)
+			}
+		})
+	}
+}
+
+func TestLoadConfigProcessRules(t *testing.T) {
+
+	dir := t.TempDir()
+	config := "processRules:\n  - id: ACME002_PROCESS\n    command: sh\n"
+	if err := os.WriteFile(filepath.Join(dir, "ctac.config.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	if _, err := LoadConfig(""); err == nil || !strings.Contains(err.Error(), "-trustConfig") {
+		t.Fatalf("got error %v but we wanted a found config file with process rules to be refused", err)
+	}
+	for name, load := range map[string]func() (*Config, error){
+		"explicit path": func() (*Config, error) { return LoadConfig("ctac.config.yaml") },
+		"trusted":       func() (*Config, error) { return LoadTrustedConfig("") },
+	} {
+		loaded, err := load()
+		if err != nil {
+			t.Fatalf("%s: loading config: %v", name, err)
+		}
+		if got := len(loaded.ProcessRules); got != 1 {
+			t.Fatalf("%s: got %d process rules but we wanted 1", name, got)
+		}
+		loaded.Close()
+	}
+
+	settings, err := LoadConfigSettings("")
+	if err != nil {
+		t.Fatalf("loading settings: %v", err)
+	}
+	if len(settings.ProcessRules) != 0 || len(settings.extraRules) != 0 {
+		t.Fatalf("got process rules %v but we wanted settings without them", settings.ProcessRules)
+	}
+}
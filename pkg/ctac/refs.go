@@ -0,0 +1,148 @@
+package ctac
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A premise can stand for a shared premise of another file with
+// `ref: facts.yaml#LATENCY_P99`, so facts used by many arguments are written
+// once. The path is relative to the referencing file; `#ID` refers to the
+// same file. Any argument file can be referenced: its premises, from every
+// argument it holds, are the shared ones.
+
+// refResolver resolves refs, caching the premises of each file.
+type refResolver struct {
+	files    map[string][]Premise
+	resolved map[string]Premise
+}
+
+// ResolveRefs replaces the premises of arguments that have a Ref with the
+// premise they refer to, read from files relative to baseDir, and validates
+// the result. A premise keeps its own Id and Line when it has them.
+// filePath is the file holding the arguments, used for `#ID` refs; it may
+// be empty for arguments that were not read from a file.
+func ResolveRefs(arguments []Argument, filePath, baseDir string) error {
+	resolver := &refResolver{files: make(map[string][]Premise), resolved: make(map[string]Premise)}
+	self := refKey(filePath, baseDir)
+	resolver.files[self] = allPremises(arguments)
+
+	for i := range arguments {
+		argument := &arguments[i]
+		for j := range argument.Premises {
+			premise := &argument.Premises[j]
+			if premise.Ref == "" {
+				continue
+			}
+			shared, err := resolver.resolve(premise.Ref, self, baseDir, nil)
+			if err != nil {
+				return argumentError(arguments, i, fmt.Errorf("premises[%d]: %w", j, err))
+			}
+			*premise = mergeRef(*premise, shared)
+		}
+		if err := argument.Validate(); err != nil {
+			return argumentError(arguments, i, err)
+		}
+	}
+	return nil
+}
+
+// argumentError names the argument in err when there are several.
+func argumentError(arguments []Argument, i int, err error) error {
+	if len(arguments) > 1 {
+		return fmt.Errorf("arguments[%d] (%q): %w", i, arguments[i].Title, err)
+	}
+	return err
+}
+
+func refKey(filePath, baseDir string) string {
+	if filePath == "" || filePath == StdinPath {
+		return StdinPath
+	}
+	if abs, err := filepath.Abs(filePath); err == nil {
+		return abs
+	}
+	return filepath.Join(baseDir, filePath)
+}
+
+// resolve returns the premise ref points to, following refs in the shared
+// file. from is the file holding the ref and stack the refs being resolved.
+func (r *refResolver) resolve(ref, from, baseDir string, stack []string) (Premise, error) {
+	path, id, ok := strings.Cut(ref, "#")
+	if !ok || id == "" {
+		return Premise{}, fmt.Errorf("ref %q: want file#ID", ref)
+	}
+	file := from
+	if path != "" {
+		file = refKey(filepath.Join(baseDir, path), baseDir)
+	}
+	key := file + "#" + id
+
+	if shared, ok := r.resolved[key]; ok {
+		return shared, nil
+	}
+	for i, seen := range stack {
+		if seen == key {
+			return Premise{}, fmt.Errorf("ref cycle: %s", strings.Join(append(stack[i:], key), " -> "))
+		}
+	}
+	stack = append(stack, key)
+
+	premises, err := r.premises(file)
+	if err != nil {
+		return Premise{}, fmt.Errorf("ref %q: %w", ref, err)
+	}
+	for _, premise := range premises {
+		if premise.Id != id {
+			continue
+		}
+		if premise.Ref != "" {
+			target, err := r.resolve(premise.Ref, file, filepath.Dir(file), stack)
+			if err != nil {
+				return Premise{}, err
+			}
+			premise = mergeRef(premise, target)
+		}
+		r.resolved[key] = premise
+		return premise, nil
+	}
+	return Premise{}, fmt.Errorf("ref %q: no premise %s in %s", ref, id, filepath.Base(file))
+}
+
+// premises returns the premises of every argument in file.
+func (r *refResolver) premises(file string) ([]Premise, error) {
+	if premises, ok := r.files[file]; ok {
+		return premises, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := DecodeArguments(data, fileFormat(file, data))
+	if err != nil {
+		return nil, err
+	}
+	r.files[file] = allPremises(arguments)
+	return r.files[file], nil
+}
+
+func allPremises(arguments []Argument) []Premise {
+	var premises []Premise
+	for _, argument := range arguments {
+		premises = append(premises, argument.Premises...)
+	}
+	return premises
+}
+
+// mergeRef fills a referencing premise from the shared one.
+func mergeRef(premise, shared Premise) Premise {
+	merged := shared
+	merged.Ref = premise.Ref
+	merged.Line = premise.Line
+	if premise.Id != "" {
+		merged.Id = premise.Id
+	}
+	return merged
+}
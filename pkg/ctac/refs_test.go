@@ -0,0 +1,116 @@
+package ctac
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResolveRefs(t *testing.T) {
+
+	facts := `premises:
+-   id: LATENCY_P99
+    text: "Our p99 latency is 340ms"
+    confidence: high
+-   id: TRAFFIC
+    ref: "more/traffic.yaml#RPS"
+`
+	cases := []struct {
+		name     string
+		files    map[string]string
+		wantText map[string]string
+		wantErr  string
+	}{
+		{
+			name: "Refs are resolved relative to each file",
+			files: map[string]string{
+				"decisions/cache.yaml": "premises:\n-   ref: ../facts.yaml#LATENCY_P99\n-   id: P2\n    ref: ../facts.yaml#TRAFFIC\n-   id: P3\n    ref: \"#P2\"\n",
+				"facts.yaml":           facts,
+				"more/traffic.yaml":    "premises:\n-   id: RPS\n    text: \"Traffic is 10k rps\"\n",
+			},
+			wantText: map[string]string{"LATENCY_P99": "Our p99 latency is 340ms", "P2": "Traffic is 10k rps", "P3": "Traffic is 10k rps"},
+		},
+		{
+			name:    "Missing files are reported",
+			files:   map[string]string{"decisions/cache.yaml": "premises:\n-   ref: ../nope.yaml#X\n"},
+			wantErr: `premises[0]: ref "../nope.yaml#X"`,
+		},
+		{
+			name:    "Missing premises are reported",
+			files:   map[string]string{"decisions/cache.yaml": "premises:\n-   ref: ../facts.yaml#NOPE\n", "facts.yaml": facts},
+			wantErr: `no premise NOPE in facts.yaml`,
+		},
+		{
+			name: "Cycles are reported",
+			files: map[string]string{
+				"decisions/cache.yaml": "premises:\n-   ref: ../a.yaml#A\n",
+				"a.yaml":               "premises:\n-   id: A\n    ref: b.yaml#B\n",
+				"b.yaml":               "premises:\n-   id: B\n    ref: a.yaml#A\n",
+			},
+			wantErr: "ref cycle",
+		},
+		{
+			name:    "Refs must name a premise",
+			files:   map[string]string{"decisions/cache.yaml": "premises:\n-   ref: ../facts.yaml\n"},
+			wantErr: "want file#ID",
+		},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, tc.files)
+			argument, err := Loader(filepath.Join(dir, "decisions/cache.yaml"))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loading: %v", err)
+			}
+			for _, premise := range argument.Premises {
+				if premise.Text != tc.wantText[premise.Id] {
+					t.Fatalf("premise %s got text %q but we wanted %q", premise.Id, premise.Text, tc.wantText[premise.Id])
+				}
+			}
+		})
+	}
+}
+
+func TestSharedPremiseEdits(t *testing.T) {
+
+	dir := writeFiles(t, map[string]string{
+		"a.yaml":     "premises:\n-   ref: facts.yaml#F\n",
+		"b.yaml":     "premises:\n-   ref: facts.yaml#F\n",
+		"facts.yaml": "premises:\n-   id: F\n    text: \"Old number\"\n",
+	})
+	if err := os.WriteFile(filepath.Join(dir, "facts.yaml"), []byte("premises:\n-   id: F\n    text: \"New number\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		argument, err := Loader(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("loading %s: %v", name, err)
+		}
+		if got := argument.Premises[0].Text; got != "New number" {
+			t.Fatalf("%s got %q but we wanted the edited shared premise", name, got)
+		}
+	}
+}
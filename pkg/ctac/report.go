@@ -0,0 +1,222 @@
+package ctac
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// ReportFormat is a format `ctac analyse` can write its results in.
+type ReportFormat string
+
+const (
+	ReportJSON        ReportFormat = "json"         // the issues of every argument, as one array
+	ReportJSONReports ReportFormat = "json-reports" // one Report per argument
+	ReportMarkdown    ReportFormat = "markdown"
+	ReportHTML        ReportFormat = "html"
+)
+
+// ParseReportFormat parses a report format name, accepting md for markdown.
+func ParseReportFormat(name string) (ReportFormat, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return ReportJSON, nil
+	case "json-reports":
+		return ReportJSONReports, nil
+	case "markdown", "md":
+		return ReportMarkdown, nil
+	case "html":
+		return ReportHTML, nil
+	}
+	return "", fmt.Errorf("unknown report format %q (want json, json-reports, markdown or html)", name)
+}
+
+// reportView is what the Markdown and HTML report templates render for one
+// argument.
+type reportView struct {
+	Argument Argument
+	Report   Report
+	Grade    string
+	Counts   string
+	Metadata []metadataField
+	Issues   []issueView
+	Ignored  []ignoredRule
+}
+
+type issueView struct {
+	Issue
+	Where string
+}
+
+// ignoredRule is a rule of the ignore spec and the number of issues it
+// suppressed.
+type ignoredRule struct {
+	RuleID string
+	Reason string
+	Issues int
+}
+
+// reportViews pairs each report with the argument it is about.
+func reportViews(arguments []Argument, reports []Report) []reportView {
+	views := make([]reportView, 0, len(reports))
+	for i, report := range reports {
+		view := reportView{Report: report, Grade: Grade(report.Score), Metadata: metadataFields(report.Metadata)}
+		if i < len(arguments) {
+			view.Argument = arguments[i]
+		}
+
+		counts := make(map[Severity]int)
+		for _, issue := range report.Issues {
+			counts[issue.Severity]++
+			view.Issues = append(view.Issues, issueView{Issue: issue, Where: issueLocation(issue)})
+		}
+		view.Counts = fmt.Sprintf("%d error%s, %d warning%s, %d info",
+			counts[SeverityError], plural(counts[SeverityError]), counts[SeverityWarning], plural(counts[SeverityWarning]), counts[SeverityInfo])
+
+		byRule := make(map[string]int)
+		for j, ignored := range report.Ignored {
+			byRule[ignored.RuleID] = j
+			view.Ignored = append(view.Ignored, ignoredRule{RuleID: ignored.RuleID, Reason: ignored.Reason})
+		}
+		for _, suppressed := range report.Suppressed {
+			j, ok := byRule[suppressed.RuleID]
+			if !ok {
+				j = len(view.Ignored)
+				byRule[suppressed.RuleID] = j
+				view.Ignored = append(view.Ignored, ignoredRule{RuleID: suppressed.RuleID, Reason: suppressed.Reason})
+			}
+			view.Ignored[j].Issues++
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// issueLocation describes where an issue is, such as "P2, line 6".
+func issueLocation(issue Issue) string {
+	var where []string
+	if issue.Premise != "" {
+		where = append(where, issue.Premise)
+	}
+	if issue.Line > 0 {
+		where = append(where, fmt.Sprintf("line %d", issue.Line))
+	}
+	return strings.Join(where, ", ")
+}
+
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(text string) string {
+	return markdownLine(strings.ReplaceAll(text, "|", `\|`))
+}
+
+// markdownLine keeps text on one line, for headings and prose.
+func markdownLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var markdownReport = template.Must(template.New("markdown").Funcs(template.FuncMap{"cell": markdownCell, "line": markdownLine}).Parse(`# ctac report
+{{range .}}
+## {{line .Argument.Title}}
+
+**Score:** {{.Report.Score}}/100 ({{.Grade}}) · {{.Counts}}
+{{with .Metadata}}
+{{range .}}- **{{.Name}}:** {{line .Value}}
+{{end}}{{end}}
+### Summary
+
+| ID | Premise | Confidence |
+|----|---------|------------|
+{{range .Argument.Premises}}| {{cell .Id}} | {{cell .Text}} | {{.Confidence}} |
+{{end}}
+**Conclusion:** {{line .Argument.Conclusion.Text}} (modality: {{.Argument.Conclusion.Modality}}, confidence: {{.Argument.Conclusion.Confidence}})
+{{with .Argument.Alternatives}}
+**Alternatives:** {{range $i, $a := .}}{{if $i}}, {{end}}{{line $a.Name}}{{end}}
+{{end}}
+### Issues
+{{if .Issues}}
+| Severity | Rule | Where | Message | Hint |
+|----------|------|-------|---------|------|
+{{range .Issues}}| {{.Severity}} | {{cell .RuleID}} | {{cell .Where}} | {{cell .Message}} | {{cell .Hint}} |
+{{end}}{{else}}
+✅ No issues found.
+{{end}}{{with .Ignored}}
+### Ignored rules
+
+| Rule | Reason | Issues |
+|------|--------|--------|
+{{range .}}| {{cell .RuleID}} | {{if .Reason}}{{cell .Reason}}{{else}}_no reason given_{{end}} | {{.Issues}} |
+{{end}}{{end}}{{end}}`))
+
+// WriteMarkdownReport writes the reports as Markdown, for example to post
+// as a pull request comment. arguments[i] is the argument of reports[i].
+func WriteMarkdownReport(w io.Writer, arguments []Argument, reports []Report) error {
+	return markdownReport.Execute(w, reportViews(arguments, reports))
+}
+
+var htmlReport = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>ctac report{{if eq (len .) 1}}: {{(index . 0).Argument.Title}}{{end}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.5; }
+h1 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; }
+section { margin-bottom: 3rem; }
+table { border-collapse: collapse; width: 100%; margin: .5rem 0 1rem; }
+th, td { border: 1px solid #d0d7de; padding: .4rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-size: .85em; }
+.score { font-size: 1.25rem; font-weight: 600; }
+.grade { display: inline-block; border-radius: 4px; padding: 0 .5rem; color: #fff; background: #57606a; }
+.grade-A, .grade-B { background: #1a7f37; }
+.grade-C, .grade-D { background: #9a6700; }
+.grade-F { background: #cf222e; }
+.severity { font-weight: 600; text-transform: uppercase; font-size: .8rem; }
+.severity-error { color: #cf222e; }
+.severity-warning { color: #9a6700; }
+.severity-info { color: #0969da; }
+.hint { color: #57606a; }
+.metadata { list-style: none; padding: 0; }
+</style>
+</head>
+<body>
+<h1>ctac report</h1>
+{{range .}}<section>
+<h2>{{.Argument.Title}}</h2>
+<p class="score">Score: {{.Report.Score}}/100 <span class="grade grade-{{.Grade}}">{{.Grade}}</span></p>
+<p>{{.Counts}}</p>
+{{with .Metadata}}<ul class="metadata">
+{{range .}}<li><strong>{{.Name}}:</strong> {{.Value}}</li>
+{{end}}</ul>
+{{end}}<h3>Summary</h3>
+<table>
+<tr><th>ID</th><th>Premise</th><th>Confidence</th></tr>
+{{range .Argument.Premises}}<tr><td>{{.Id}}</td><td>{{.Text}}</td><td>{{.Confidence}}</td></tr>
+{{end}}</table>
+<p><strong>Conclusion:</strong> {{.Argument.Conclusion.Text}} (modality: {{.Argument.Conclusion.Modality}}, confidence: {{.Argument.Conclusion.Confidence}})</p>
+{{with .Argument.Alternatives}}<p><strong>Alternatives:</strong> {{range $i, $a := .}}{{if $i}}, {{end}}{{$a.Name}}{{end}}</p>
+{{end}}<h3>Issues</h3>
+{{if .Issues}}<table>
+<tr><th>Severity</th><th>Rule</th><th>Where</th><th>Message</th><th>Hint</th></tr>
+{{range .Issues}}<tr><td class="severity severity-{{.Severity}}">{{.Severity}}</td><td><code>{{.RuleID}}</code></td><td>{{.Where}}</td><td>{{.Message}}</td><td class="hint">{{.Hint}}</td></tr>
+{{end}}</table>
+{{else}}<p>✅ No issues found.</p>
+{{end}}{{with .Ignored}}<h3>Ignored rules</h3>
+<table>
+<tr><th>Rule</th><th>Reason</th><th>Issues</th></tr>
+{{range .}}<tr><td><code>{{.RuleID}}</code></td><td>{{if .Reason}}{{.Reason}}{{else}}<em>no reason given</em>{{end}}</td><td>{{.Issues}}</td></tr>
+{{end}}</table>
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+// WriteHTMLReport writes the reports as a self-contained HTML page, with
+// its styles inline and no external assets. arguments[i] is the argument
+// of reports[i].
+func WriteHTMLReport(w io.Writer, arguments []Argument, reports []Report) error {
+	return htmlReport.Execute(w, reportViews(arguments, reports))
+}
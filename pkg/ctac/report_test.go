@@ -0,0 +1,145 @@
+package ctac
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func reportFixture(t *testing.T) ([]Argument, []Report) {
+	t.Helper()
+
+	argument := Argument{
+		Title: "Move to <microservices>",
+		Metadata: Metadata{
+			Status:     StatusAccepted,
+			DecidedAt:  mustDate(t, "2026-02-01"),
+			Authors:    []string{"ana"},
+			Reviewers:  []string{"ben", "cy"},
+			Tags:       []string{"architecture"},
+			Supersedes: []string{"adr/0003-monolith.yaml"},
+		},
+		Premises: []Premise{
+			{Id: "P1", Text: "Deploys take 40 minutes | sometimes more", Confidence: High, Line: 3},
+			{Id: "P2", Text: "Some engineers say deploys are obviously too slow", Confidence: Low, Line: 6},
+		},
+		Conclusion: Conclusion{Text: "We must move to microservices", Modality: ModalityMust, Confidence: High, Line: 9},
+	}
+	ignore := &IgnoreSpec{
+		Rules:  []string{"CTAC002_VAGUENESS_DETECTED", "CTAC008_DUPLICATE_PREMISES"},
+		Reason: []string{"The survey's own wording", "Premises are short"},
+	}
+	report := NewAnalyzer(WithIgnore(ignore)).Analyze(t.Context(), argument)
+	return []Argument{argument}, []Report{report}
+}
+
+func TestWriteMarkdownReport(t *testing.T) {
+
+	arguments, reports := reportFixture(t)
+	var b bytes.Buffer
+	if err := WriteMarkdownReport(&b, arguments, reports); err != nil {
+		t.Fatalf("writing report: %v", err)
+	}
+	got := b.String()
+
+	want := []string{
+		"## Move to <microservices>\n",
+		"**Score:** ",
+		"- **Status:** accepted\n- **Decided at:** 2026-02-01\n- **Authors:** ana\n- **Reviewers:** ben, cy\n- **Tags:** architecture\n- **Supersedes:** adr/0003-monolith.yaml\n",
+		"| P1 | Deploys take 40 minutes \\| sometimes more | high |\n",
+		"**Conclusion:** We must move to microservices (modality: must, confidence: high)\n",
+		"| error | CTAC007_EMOTIONAL_LANGUAGE_DETECTED | P2, line 6 | ",
+		"| Please rewrite the premises without using unnecessary emotional language' |\n",
+		"### Ignored rules",
+		"| CTAC002_VAGUENESS_DETECTED | The survey's own wording | 1 |\n",
+		"| CTAC008_DUPLICATE_PREMISES | Premises are short | 0 |\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("got report\n%s\nwant it to contain %q", got, w)
+		}
+	}
+	if strings.Contains(got, "\n\n\n") {
+		t.Errorf("got several blank lines in a row:\n%s", got)
+	}
+}
+
+func TestMarkdownReportEscapesFields(t *testing.T) {
+
+	argument := Argument{
+		Title:        "Queues\n## or not",
+		Metadata:     Metadata{Tags: []string{"a|b", "c\nd"}},
+		Premises:     []Premise{{Id: "P|1", Text: "Queues decouple services", Confidence: High}},
+		Conclusion:   Conclusion{Text: "Use\nqueues", Modality: ModalityShould, Confidence: Medium},
+		Alternatives: []Alternative{{Name: "Kafka\n# topics"}},
+	}
+	report := Report{
+		Title:    argument.Title,
+		Metadata: argument.Metadata,
+		Issues:   []Issue{{RuleID: "ACME|001", Severity: SeverityError, Message: "Too\nfew premises", Premise: "P|1"}},
+		Ignored:  []IgnoredRule{{RuleID: "ACME|002"}},
+	}
+	var b bytes.Buffer
+	if err := WriteMarkdownReport(&b, []Argument{argument}, []Report{report}); err != nil {
+		t.Fatalf("writing report: %v", err)
+	}
+	got := b.String()
+
+	want := []string{
+		"## Queues ## or not\n",
+		"- **Tags:** a|b, c d\n",
+		"| P\\|1 | Queues decouple services | high |\n",
+		"**Conclusion:** Use queues (",
+		"**Alternatives:** Kafka # topics\n",
+		"| error | ACME\\|001 | P\\|1 | Too few premises |",
+		"| ACME\\|002 | _no reason given_ | 0 |\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("got report\n%s\nwant it to contain %q", got, w)
+		}
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+
+	arguments, reports := reportFixture(t)
+	var b bytes.Buffer
+	if err := WriteHTMLReport(&b, arguments, reports); err != nil {
+		t.Fatalf("writing report: %v", err)
+	}
+	got := b.String()
+
+	want := []string{
+		"<title>ctac report: Move to &lt;microservices&gt;</title>",
+		"<style>",
+		`<span class="grade grade-`,
+		`<td class="severity severity-error">error</td><td><code>CTAC007_EMOTIONAL_LANGUAGE_DETECTED</code></td><td>P2, line 6</td>`,
+		`<td><code>CTAC002_VAGUENESS_DETECTED</code></td><td>The survey&#39;s own wording</td><td>1</td>`,
+		`<td><code>CTAC008_DUPLICATE_PREMISES</code></td><td>Premises are short</td><td>0</td>`,
+		"<li><strong>Reviewers:</strong> ben, cy</li>",
+		"<li><strong>Supersedes:</strong> adr/0003-monolith.yaml</li>",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("got report\n%s\nwant it to contain %q", got, w)
+		}
+	}
+	for _, external := range []string{"<link", "<script", "src=", "http://", "https://"} {
+		if strings.Contains(got, external) {
+			t.Errorf("got %q in a report that should be self-contained", external)
+		}
+	}
+}
+
+func TestParseReportFormat(t *testing.T) {
+
+	for name, want := range map[string]ReportFormat{"json": ReportJSON, "json-reports": ReportJSONReports, "md": ReportMarkdown, "Markdown": ReportMarkdown, "html": ReportHTML} {
+		if got, err := ParseReportFormat(name); err != nil || got != want {
+			t.Errorf("got %q, %v for %q but we wanted %q", got, err, name, want)
+		}
+	}
+	if _, err := ParseReportFormat("pdf"); err == nil {
+		t.Errorf("got no error for an unknown format")
+	}
+}
@@ -1,10 +1,10 @@
 package ctac
 
 import (
+	"context"
 	"fmt"
-	"regexp"
 	"strings"
-	"sync"
+	"time"
 )
 
 type Rule interface {
@@ -12,11 +12,53 @@ type Rule interface {
 	Check(a Argument) []Issue
 }
 
+// Issue is a problem found by a rule. Line is the line of the premise or
+// conclusion in the argument file, or 0 when it is unknown. Premise is the
+// ID of the premise the issue is about, and Words the words of its text
+// that triggered the issue, for reports to highlight.
 type Issue struct {
 	RuleID   string
 	Severity Severity
 	Message  string
 	Hint     string
+	Line     int          `json:",omitempty"`
+	Premise  string       `json:",omitempty"`
+	Words    []string     `json:",omitempty"`
+	Argument *ArgumentRef `json:",omitempty"`
+}
+
+// ArgumentRef identifies the argument an issue was found in, for files
+// holding several. Line is where the argument starts in the file.
+type ArgumentRef struct {
+	Index int
+	Title string
+	Line  int `json:",omitempty"`
+}
+
+// RuleError reports that a rule could not run to completion, such as a
+// plugin that timed out.
+type RuleError struct {
+	RuleID string
+	Err    error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %s failed: %v", e.RuleID, e.Err)
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// ruleErrorIssue reports a rule error as an issue, for callers that only
+// look at issues.
+func ruleErrorIssue(err *RuleError) Issue {
+	return Issue{
+		RuleID:   err.RuleID,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("Rule %s could not check this argument: %v", err.RuleID, err.Err),
+		Hint:     "Fix or disable the rule; its checks did not run",
+	}
 }
 
 type Severity string
@@ -28,12 +70,74 @@ const (
 )
 
 type MissingPremiseRule struct{}
-type VaguenessDetector struct{}
 type MissingConclusionRule struct{}
 type SinglePremiseRule struct{}
 type ModalityMismatchRule struct{}
-type QuantificationRequiredRule struct{}
-type EmotionalLanguageDetector struct{}
+
+// The lexicon rules pick the lexicons matching the argument's language.
+// A nil Lexicons uses the bundled packs.
+type QuantificationRequiredRule struct {
+	Lexicons *Lexicons
+}
+
+// VaguenessDetector and EmotionalLanguageDetector report words in quotation
+// marks, or in the quote of a cited source, according to Quotes. The zero
+// value downgrades them.
+type VaguenessDetector struct {
+	Quotes   QuoteMode
+	Lexicons *Lexicons
+}
+
+type EmotionalLanguageDetector struct {
+	Quotes   QuoteMode
+	Lexicons *Lexicons
+}
+
+// DuplicatePremiseRule flags premise pairs whose stemmed word sets overlap by
+// more than Threshold (Jaccard similarity). A zero Threshold uses the default.
+type DuplicatePremiseRule struct {
+	Threshold float64
+}
+
+const defaultDuplicateThreshold = 0.7
+
+// AssumptionRule checks the assumptions cited by an argument and its
+// premises against Registry: unknown IDs are errors and assumptions past
+// their review date are warnings. Today defaults to the current date. A nil
+// Registry disables the rule.
+type AssumptionRule struct {
+	Registry *AssumptionRegistry
+	Today    Date
+}
+
+// AcceptedWithErrorsRule rejects accepted decisions that still have
+// error-severity issues. It is an IssueRule: Check alone reports nothing.
+type AcceptedWithErrorsRule struct{}
+
+// StaleEvidenceRule flags premises and sources past their expiry date or
+// with an asOf date more than MaxAgeDays ago. Zero values use a year and
+// the current date.
+type StaleEvidenceRule struct {
+	MaxAgeDays int
+	Today      Date
+}
+
+// FalseDilemmaRule flags arguments that weigh exactly two alternatives and
+// premises framed as either/or choices, such as "either we migrate or we
+// fall behind". Quoted framing is not reported.
+type FalseDilemmaRule struct {
+	Lexicons *Lexicons
+}
+
+// MustWithoutAlternativesRule flags must conclusions reached without
+// listing any alternative.
+type MustWithoutAlternativesRule struct{}
+
+// TopOptionRule flags conclusions that do not name the top-ranked option of
+// the argument's decision matrix. Options are matched by name as whole
+// words. A name with capitals, such as Go, must keep them, so "we should go
+// with Rust" names Rust only; a lowercase name matches in any case.
+type TopOptionRule struct{}
 
 func (r MissingPremiseRule) ID() string {
 	return "CTAC001_MISSING_PREMISES"
@@ -63,87 +167,144 @@ func (rule EmotionalLanguageDetector) ID() string {
 	return "CTAC007_EMOTIONAL_LANGUAGE_DETECTED"
 }
 
-type vaguePhrase struct {
-	Phrase string
-	Reg    *regexp.Regexp
-}
-
-var vaguePhrases = []vaguePhrase{
-	{
-		Phrase: "someone",
-		Reg:    regexp.MustCompile(`(?i)\bsomeone\b`),
-	},
-	{
-		Phrase: "some",
-		Reg:    regexp.MustCompile(`(?i)\bsome\b`),
-	},
-	{
-		Phrase: "everyone thinks",
-		Reg:    regexp.MustCompile(`(?i)\beveryone thinks\b`),
-	},
-	{
-		Phrase: "maybe",
-		Reg:    regexp.MustCompile(`(?i)\bmaybe\b`),
-	},
-	{
-		Phrase: "everyone knows",
-		Reg:    regexp.MustCompile(`(?i)\beveryone knows\b`),
-	},
-}
-
-type emotionalLanguagePhrase struct {
-	Phrase string
-	Reg    *regexp.Regexp
-}
-
-var negativeEmotionWords = []string{"terrible", "horrible", "disastrous", "catastrophic", "evil", "awful", "tragic", "shocking", "outrageous"}
-var positiveEmotionWords = []string{"amazing", "brilliant", "fantastic", "heroic", "wonderful", "incredible", "terrific"}
-var persuasiveIntensifiers = []string{"obviously", "clearly", "undeniably", "absolutely", "definitively"}
-
-func buildPhrases(words []string) []emotionalLanguagePhrase {
-	phrases := make([]emotionalLanguagePhrase, 0, len(words))
-	for _, w := range words {
-		pattern := fmt.Sprintf(`(?i)\b%s\b`, regexp.QuoteMeta(w))
-		phrases = append(phrases, emotionalLanguagePhrase{
-			Phrase: w,
-			Reg:    regexp.MustCompile(pattern),
-		})
+func (rule DuplicatePremiseRule) ID() string {
+	return "CTAC008_DUPLICATE_PREMISES"
+}
+
+func (rule AssumptionRule) ID() string {
+	return "CTAC009_ASSUMPTIONS"
+}
+
+func (rule StaleEvidenceRule) ID() string {
+	return "CTAC010_STALE_EVIDENCE"
+}
+
+func (rule FalseDilemmaRule) ID() string {
+	return "CTAC012_FALSE_DILEMMA"
+}
+
+func (rule MustWithoutAlternativesRule) ID() string {
+	return "CTAC013_MUST_WITHOUT_ALTERNATIVES"
+}
+
+func (rule TopOptionRule) ID() string {
+	return "CTAC014_CONCLUSION_NOT_TOP_OPTION"
+}
+
+func (rule AcceptedWithErrorsRule) ID() string {
+	return "CTAC011_ACCEPTED_WITH_ERRORS"
+}
+
+// rank orders severities from least to most severe.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
 	}
-	return phrases
 }
 
-var negativePhrases = buildPhrases(negativeEmotionWords)
-var positivePhrases = buildPhrases(positiveEmotionWords)
-var intensifierPhrases = buildPhrases(persuasiveIntensifiers)
+// lower returns the next less severe severity.
+func (s Severity) lower() Severity {
+	switch s {
+	case SeverityError:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// spotPhrases returns the phrases found in a premise, split between the
+// author's own words and words quoted from someone else: text inside
+// quotation marks and the quotes of the premise's sources. With
+// QuoteModeInclude quoted words count as the author's own.
+func spotPhrases(p Premise, lexicons *languageLexicons, name string, keep func(textMatch) bool, mode QuoteMode) (own, quoted []lexiconPhrase) {
+	text := lexicons.analyse(p.Text)
+	sourceQuotes := make([]analysedText, 0, len(p.Sources))
+	for _, source := range p.Sources {
+		if source.Quote != "" {
+			sourceQuotes = append(sourceQuotes, lexicons.analyse(source.Quote))
+		}
+	}
+
+	for _, phrase := range lexicons.phrases[name] {
+		if text.contains(phrase, func(m textMatch) bool { return keep(m) && (!m.Quoted || mode == QuoteModeInclude) }) {
+			own = append(own, phrase)
+			continue
+		}
+		inQuote := text.contains(phrase, func(m textMatch) bool { return keep(m) && m.Quoted })
+		for _, quote := range sourceQuotes {
+			inQuote = inQuote || quote.contains(phrase, keep)
+		}
+		if !inQuote {
+			continue
+		}
+		if mode == QuoteModeInclude {
+			own = append(own, phrase)
+		} else {
+			quoted = append(quoted, phrase)
+		}
+	}
 
-var regexDigit = regexp.MustCompile("[0-9]+")
-var regexQuantificationPhrase = regexp.MustCompile(`(?i)(\bsignificant|\bdecrease|\bmost\b|\bincrease|\bdecline\b|\bpercent(age?)\b|%|\bmore\b|\bless\b|\brate\b|\btrend\b)`)
+	if mode == QuoteModeSkip {
+		quoted = nil
+	}
+	return own, quoted
+}
+
+// matchedWords returns the text matched by the phrases at positions
+// accepted by keep, without repeats.
+func matchedWords(text analysedText, phrases []lexiconPhrase, keep func(textMatch) bool) []string {
+	var found []string
+	seen := make(map[string]bool)
+	for _, phrase := range phrases {
+		for _, m := range text.find(phrase.locate(text.Text)) {
+			word := text.Text[m.Start:m.End]
+			if keep(m) && !seen[word] {
+				seen[word] = true
+				found = append(found, word)
+			}
+		}
+	}
+	return found
+}
 
 func (rule VaguenessDetector) Check(argument Argument) []Issue {
 	var issues []Issue
 
 	premises := argument.Premises
-	var spottedVagueWords string
+	lexicons := rule.Lexicons.forArgument(argument)
 
 	for _, p := range premises {
 
-		for _, vaguePhrase := range vaguePhrases {
-			if vaguePhrase.Reg.MatchString(p.Text) {
-
-				spottedVagueWords = spottedVagueWords + ", " + vaguePhrase.Phrase
-			}
-		}
+		spottedVagueWords, quotedVagueWords := spotPhrases(p, lexicons, LexiconVague, affirmedWithoutNumber, rule.Quotes)
+		text := lexicons.analyse(p.Text)
 		if len(spottedVagueWords) > 0 {
 
 			issues = append(issues, Issue{
 				RuleID:   rule.ID(),
-				Severity: SeverityWarning,
-				Message:  fmt.Sprintf("Premise %s %q contains vague words '%s'", p.Id, p.Text, strings.TrimLeft(spottedVagueWords, " ,")),
+				Severity: phraseSeverity(spottedVagueWords, SeverityWarning),
+				Message:  fmt.Sprintf("Premise %s %q contains vague words '%s'", p.Id, p.Text, strings.Join(phraseNames(spottedVagueWords), ", ")),
 				Hint:     "Remove use of vague words by using more precise language",
+				Line:     p.Line,
+				Premise:  p.Id,
+				Words:    matchedWords(text, spottedVagueWords, affirmedWithoutNumber),
+			})
+		}
+		if len(quotedVagueWords) > 0 {
+			issues = append(issues, Issue{
+				RuleID:   rule.ID(),
+				Severity: phraseSeverity(quotedVagueWords, SeverityWarning).lower(),
+				Message:  fmt.Sprintf("Premise %s %q quotes vague words '%s'", p.Id, p.Text, strings.Join(phraseNames(quotedVagueWords), ", ")),
+				Hint:     "Quoted words are not attributed to you, but check that the quote is precise enough to support the premise",
+				Line:     p.Line,
+				Premise:  p.Id,
+				Words:    matchedWords(text, quotedVagueWords, affirmedWithoutNumber),
 			})
 		}
-
-		spottedVagueWords = ""
 	}
 	return issues
 }
@@ -205,6 +366,7 @@ func (r ModalityMismatchRule) Check(argument Argument) []Issue {
 				Severity: SeverityError,
 				Message:  "Strong conclusion modality (‘must’) with weak/insufficient support.",
 				Hint:     "Add at least one high-confidence premise or lower the modality (‘must’ → ‘should’)",
+				Line:     argument.Conclusion.Line,
 			}}
 		}
 	}
@@ -216,16 +378,25 @@ func (rule QuantificationRequiredRule) Check(argument Argument) []Issue {
 	var issues []Issue
 
 	premises := argument.Premises
+	lexicons := rule.Lexicons.forArgument(argument)
 
 	for _, p := range premises {
 
-		if regexQuantificationPhrase.MatchString(p.Text) && !regexDigit.MatchString(p.Text) {
+		text := lexicons.analyse(p.Text)
+		unquantified := matchedWords(text, lexicons.phrases[LexiconQuantifiers], func(m textMatch) bool {
+			return affirmedWithoutNumber(m) && !text.sentenceHasNumber(m.Sentence)
+		})
+
+		if len(unquantified) > 0 {
 
 			issues = append(issues, Issue{
 				RuleID:   rule.ID(),
 				Severity: SeverityError,
 				Message:  fmt.Sprintf("Premise %s '%q' uses quantification but omits reference to actual numbers", p.Id, p.Text),
 				Hint:     "Provide a number (e.g., ‘18%’) or sample size supporting significant/most/increase'",
+				Line:     p.Line,
+				Premise:  p.Id,
+				Words:    unquantified,
 			})
 		}
 	}
@@ -238,38 +409,40 @@ func (rule EmotionalLanguageDetector) Check(argument Argument) []Issue {
 	var issues []Issue
 
 	premises := argument.Premises
-	var spottedEmotionalWords string
+	lexicons := rule.Lexicons.forArgument(argument)
 
 	for _, p := range premises {
 
-		for _, np := range negativePhrases {
-			if np.Reg.MatchString(p.Text) {
-				spottedEmotionalWords = spottedEmotionalWords + ", " + np.Phrase
-			}
-		}
-
-		for _, pp := range positivePhrases {
-			if pp.Reg.MatchString(p.Text) {
-				spottedEmotionalWords = spottedEmotionalWords + ", " + pp.Phrase
-			}
-		}
-
-		for _, ip := range intensifierPhrases {
-			if ip.Reg.MatchString(p.Text) {
-				spottedEmotionalWords = spottedEmotionalWords + ", " + ip.Phrase
-			}
+		var spottedEmotionalWords, quotedEmotionalWords []lexiconPhrase
+		for _, name := range []string{LexiconNegativeEmotion, LexiconPositiveEmotion, LexiconIntensifiers} {
+			own, quoted := spotPhrases(p, lexicons, name, affirmed, rule.Quotes)
+			spottedEmotionalWords = append(spottedEmotionalWords, own...)
+			quotedEmotionalWords = append(quotedEmotionalWords, quoted...)
 		}
 
+		text := lexicons.analyse(p.Text)
 		if len(spottedEmotionalWords) > 0 {
 			issues = append(issues, Issue{
 				RuleID:   rule.ID(),
-				Severity: SeverityError,
-				Message:  fmt.Sprintf("Premise %s '%q' uses emotional language %s", p.Id, p.Text, strings.TrimLeft(spottedEmotionalWords, " ,")),
+				Severity: phraseSeverity(spottedEmotionalWords, SeverityError),
+				Message:  fmt.Sprintf("Premise %s '%q' uses emotional language %s", p.Id, p.Text, strings.Join(phraseNames(spottedEmotionalWords), ", ")),
 				Hint:     "Please rewrite the premises without using unnecessary emotional language'",
+				Line:     p.Line,
+				Premise:  p.Id,
+				Words:    matchedWords(text, spottedEmotionalWords, affirmed),
+			})
+		}
+		if len(quotedEmotionalWords) > 0 {
+			issues = append(issues, Issue{
+				RuleID:   rule.ID(),
+				Severity: phraseSeverity(quotedEmotionalWords, SeverityError).lower(),
+				Message:  fmt.Sprintf("Premise %s '%q' quotes emotional language %s", p.Id, p.Text, strings.Join(phraseNames(quotedEmotionalWords), ", ")),
+				Hint:     "Quoted words are not attributed to you, but avoid relying on emotionally loaded quotes as evidence",
+				Line:     p.Line,
+				Premise:  p.Id,
+				Words:    matchedWords(text, quotedEmotionalWords, affirmed),
 			})
 		}
-
-		spottedEmotionalWords = ""
 
 	}
 
@@ -277,95 +450,277 @@ func (rule EmotionalLanguageDetector) Check(argument Argument) []Issue {
 
 }
 
-func RunAllRulesSequential(a Argument) []Issue {
-	rules := []Rule{
-		MissingPremiseRule{},
-		VaguenessDetector{},
-		MissingConclusionRule{},
-		SinglePremiseRule{},
-		ModalityMismatchRule{},
-		QuantificationRequiredRule{},
-		EmotionalLanguageDetector{},
-	}
+func (rule DuplicatePremiseRule) Check(argument Argument) []Issue {
+
 	var issues []Issue
 
-	for _, r := range rules {
-		issues = append(issues, r.Check(a)...)
+	threshold := rule.Threshold
+	if threshold <= 0 {
+		threshold = defaultDuplicateThreshold
+	}
+
+	stems := make([]map[string]bool, len(argument.Premises))
+	for i, p := range argument.Premises {
+		stems[i] = stemSet(p.Text)
+	}
+
+	for i := 0; i < len(argument.Premises); i++ {
+		for j := i + 1; j < len(argument.Premises); j++ {
+			similarity := jaccard(stems[i], stems[j])
+			if similarity < threshold {
+				continue
+			}
+			first, second := argument.Premises[i], argument.Premises[j]
+			issues = append(issues, Issue{
+				RuleID:   rule.ID(),
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("Premises %s and %s are %.0f%% similar and may repeat the same claim: %q / %q", first.Id, second.Id, similarity*100, first.Text, second.Text),
+				Hint:     fmt.Sprintf("Merge %s and %s into a single premise so the support is not counted twice", first.Id, second.Id),
+				Line:     second.Line,
+				Premise:  second.Id,
+			})
+		}
 	}
+
 	return issues
 }
 
-func RunAllRulesParallel(a Argument, maxWorkers int) []Issue {
-	rules := []Rule{
-		MissingPremiseRule{},
-		VaguenessDetector{},
-		MissingConclusionRule{},
-		SinglePremiseRule{},
-		ModalityMismatchRule{},
-		QuantificationRequiredRule{},
-		EmotionalLanguageDetector{},
-	}
+func (rule AssumptionRule) Check(argument Argument) []Issue {
 
-	type job struct {
-		idx  int
-		rule Rule
+	if rule.Registry == nil {
+		return nil
+	}
+	today := rule.Today
+	if today.IsZero() {
+		today = DateOf(time.Now())
 	}
 
-	type result struct {
-		idx    int
-		issues []Issue
+	var issues []Issue
+	check := func(who, premise string, ids []string, line int) {
+		for _, id := range ids {
+			assumption, ok := rule.Registry.Get(id)
+			if !ok {
+				issues = append(issues, Issue{
+					RuleID:   rule.ID(),
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("%s cites assumption %s, which is not in the assumptions registry", who, id),
+					Hint:     fmt.Sprintf("Add %s to the assumptions file or fix the ID", id),
+					Line:     line,
+					Premise:  premise,
+				})
+				continue
+			}
+			if !assumption.ReviewDate.IsZero() && assumption.ReviewDate.Before(today.Time) {
+				issues = append(issues, Issue{
+					RuleID:   rule.ID(),
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("%s relies on assumption %s (owner: %s), which was due for review on %s", who, id, assumption.Owner, assumption.ReviewDate),
+					Hint:     fmt.Sprintf("Ask %s to review %s and update its confidence and review date", assumption.Owner, id),
+					Line:     line,
+					Premise:  premise,
+				})
+			}
+		}
 	}
 
-	jobs := make(chan job, len(rules))
-	results := make(chan result, len(rules))
+	check("This argument", "", argument.Assumptions, argument.Line)
+	for _, p := range argument.Premises {
+		check("Premise "+p.Id, p.Id, p.Assumptions, p.Line)
+	}
+	return issues
+}
 
-	worker := func(id int, jobs <-chan job, results chan<- result) {
-		for j := range jobs {
-			issues := j.rule.Check(a)
+func (rule StaleEvidenceRule) Check(argument Argument) []Issue {
 
-			results <- result{
-				idx:    j.idx,
-				issues: issues,
-			}
+	var issues []Issue
+	for _, stale := range FindStaleEvidence(argument, rule.MaxAgeDays, rule.Today) {
+		hint := "Check that the evidence still holds and update its asOf date, or replace it with recent evidence"
+		if stale.Expired {
+			hint = "Replace the expired evidence or remove the premise"
 		}
+		issues = append(issues, Issue{
+			RuleID:   rule.ID(),
+			Severity: SeverityWarning,
+			Message:  stale.String(),
+			Hint:     hint,
+			Line:     stale.Line,
+			Premise:  stale.PremiseID,
+		})
 	}
+	return issues
+}
 
-	if maxWorkers <= 0 || maxWorkers > len(rules) {
-		maxWorkers = len(rules)
+func (rule AcceptedWithErrorsRule) Check(argument Argument) []Issue {
+	return nil
+}
+
+func (rule AcceptedWithErrorsRule) CheckIssues(argument Argument, issues []Issue) []Issue {
+
+	if argument.Status != StatusAccepted {
+		return nil
+	}
+	var ruleIDs []string
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Severity == SeverityError && !seen[issue.RuleID] {
+			seen[issue.RuleID] = true
+			ruleIDs = append(ruleIDs, issue.RuleID)
+		}
+	}
+	if len(ruleIDs) == 0 {
+		return nil
 	}
+	return []Issue{{
+		RuleID:   rule.ID(),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("This decision is accepted but still has errors from %s", strings.Join(ruleIDs, ", ")),
+		Hint:     "Fix the errors, or set the status back to proposed until they are fixed",
+	}}
+}
 
-	var waitGroup sync.WaitGroup
-	waitGroup.Add((maxWorkers))
+func (rule FalseDilemmaRule) Check(argument Argument) []Issue {
 
-	// start workers with a wait group
-	for w := 0; w < maxWorkers; w++ {
-		go func(id int) {
-			defer waitGroup.Done()
-			worker(id, jobs, results)
-		}(w)
+	var issues []Issue
+
+	if len(argument.Alternatives) == 2 {
+		issues = append(issues, Issue{
+			RuleID:   rule.ID(),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("Only two alternatives are considered (%s and %s), which may be a false dilemma", argument.Alternatives[0].Name, argument.Alternatives[1].Name),
+			Hint:     "Look for other options, such as a hybrid, a smaller step or doing nothing, and list them under alternatives",
+			Line:     argument.Alternatives[0].Line,
+		})
 	}
 
-	for i, r := range rules {
-		jobs <- job{idx: i, rule: r}
+	lexicons := rule.Lexicons.forArgument(argument)
+	for _, p := range argument.Premises {
+		framing, _ := spotPhrases(p, lexicons, LexiconDilemma, affirmed, QuoteModeSkip)
+		text := lexicons.analyse(p.Text)
+		if len(framing) > 0 {
+			issues = append(issues, Issue{
+				RuleID:   rule.ID(),
+				Severity: phraseSeverity(framing, SeverityWarning),
+				Message:  fmt.Sprintf("Premise %s %q presents the choice as either/or with '%s'", p.Id, p.Text, strings.Join(phraseNames(framing), ", ")),
+				Hint:     "Check whether other options exist and list them under alternatives",
+				Line:     p.Line,
+				Premise:  p.Id,
+				Words:    matchedWords(text, framing, func(m textMatch) bool { return affirmed(m) && !m.Quoted }),
+			})
+		}
 	}
+	return issues
+}
 
-	close(jobs)
+func (rule MustWithoutAlternativesRule) Check(argument Argument) []Issue {
 
-	go func() {
-		waitGroup.Wait()
-		close(results)
-	}()
+	if argument.Conclusion.Modality != ModalityMust || len(argument.Alternatives) > 0 {
+		return nil
+	}
+	return []Issue{{
+		RuleID:   rule.ID(),
+		Severity: SeverityWarning,
+		Message:  "The conclusion says we must act, but no alternatives were considered",
+		Hint:     "List the alternatives you weighed under alternatives, with their pros, cons and why they were rejected",
+		Line:     argument.Conclusion.Line,
+	}}
+}
 
-	collected := make([][]Issue, len(rules))
+func (rule TopOptionRule) Check(argument Argument) []Issue {
 
-	for res := range results {
-		collected[res.idx] = res.issues
+	matrix, err := NewMatrix(argument)
+	if err != nil || strings.TrimSpace(argument.Conclusion.Text) == "" {
+		return nil
+	}
+	names := func(rank func(int) bool) []string {
+		var found []string
+		for _, option := range matrix.Options {
+			if !rank(option.Rank) {
+				continue
+			}
+			phrases, err := buildPhrases([]LexiconEntry{{Term: option.Name}})
+			if err != nil {
+				continue
+			}
+			for _, loc := range phrases[0].locate(argument.Conclusion.Text) {
+				if match := argument.Conclusion.Text[loc[0]:loc[1]]; match == option.Name || option.Name == strings.ToLower(option.Name) {
+					found = append(found, option.Name)
+					break
+				}
+			}
+		}
+		return found
+	}
+	if len(names(func(rank int) bool { return rank == 1 })) > 0 {
+		return nil
 	}
 
-	var all []Issue
-	for _, iss := range collected {
-		all = append(all, iss...)
+	top := matrix.Options[0]
+	message := fmt.Sprintf("The conclusion does not name %q, the top-ranked option of the decision matrix (total %s)", top.Name, formatNumber(top.Total))
+	if chosen := names(func(rank int) bool { return rank > 1 }); len(chosen) > 0 {
+		message = fmt.Sprintf("The conclusion names %s, but %q ranks first in the decision matrix (total %s)", strings.Join(chosen, ", "), top.Name, formatNumber(top.Total))
 	}
+	return []Issue{{
+		RuleID:   rule.ID(),
+		Severity: SeverityWarning,
+		Message:  message,
+		Hint:     "Revisit the weights and scores, or explain why the top-ranked option is not chosen",
+		Line:     argument.Conclusion.Line,
+	}}
+}
 
-	return all
+// BuiltinRules returns the built-in rules configured from config.
+// A nil config uses the defaults of every rule.
+func BuiltinRules(config *Config) []Rule {
+	if config == nil {
+		config = &Config{}
+	}
+	return []Rule{
+		MissingPremiseRule{},
+		VaguenessDetector{Quotes: config.Quotes.Mode, Lexicons: config.lexicons},
+		MissingConclusionRule{},
+		SinglePremiseRule{},
+		ModalityMismatchRule{},
+		QuantificationRequiredRule{Lexicons: config.lexicons},
+		EmotionalLanguageDetector{Quotes: config.Quotes.Mode, Lexicons: config.lexicons},
+		DuplicatePremiseRule{Threshold: config.DuplicatePremises.Threshold},
+		AssumptionRule{Registry: config.assumptions},
+		StaleEvidenceRule{MaxAgeDays: config.Freshness.MaxAgeDays},
+		FalseDilemmaRule{Lexicons: config.lexicons},
+		MustWithoutAlternativesRule{},
+		TopOptionRule{},
+		AcceptedWithErrorsRule{},
+	}
+}
+
+// ConfiguredRules returns the built-in rules followed by the custom rules
+// and plugins of a compiled config.
+func ConfiguredRules(config *Config) []Rule {
+	rules := BuiltinRules(config)
+	if config != nil {
+		rules = append(rules, config.extraRules...)
+	}
+	return rules
+}
+
+func RunAllRulesSequential(a Argument) []Issue {
+	return RunRulesSequential(a, BuiltinRules(nil))
+}
+
+// RunRulesSequential runs the rules one after the other. Rules that fail
+// are reported as error issues; see Engine for separate errors.
+func RunRulesSequential(a Argument, rules []Rule) []Issue {
+	return issuesWithErrors(Engine{Rules: rules}.RunRules(context.Background(), a))
+}
+
+func RunAllRulesParallel(a Argument, maxWorkers int) []Issue {
+	return RunRulesParallel(a, BuiltinRules(nil), maxWorkers)
+}
+
+// RunRulesParallel runs the rules on up to maxWorkers goroutines, keeping
+// the issues in rule order. Rules that fail are reported as error issues.
+func RunRulesParallel(a Argument, rules []Rule, maxWorkers int) []Issue {
+	if maxWorkers <= 0 {
+		maxWorkers = len(rules)
+	}
+	return issuesWithErrors(Engine{Rules: rules, MaxWorkers: maxWorkers}.RunRules(context.Background(), a))
 }
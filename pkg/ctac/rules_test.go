@@ -98,6 +98,34 @@ func TestVaguenessDetector(t *testing.T) {
 				},
 			},
 			wantIssues: 2,
+		},
+		{
+			name: "Quantifier next to a number is not vague",
+			argument: Argument{
+				Title: "Number-adjacent quantifier",
+				Premises: []Premise{
+					{Text: "Some 42% of users slack off when working from home"},
+					{Text: "It is not maybe, it is measured"},
+				},
+				Conclusion: Conclusion{
+					Text: "Working from home should be banned",
+				},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "A name containing a digit is not a number",
+			argument: Argument{
+				Title: "Digit in a name",
+				Premises: []Premise{
+					{Text: "Some S3 buckets are slow"},
+					{Text: "Some mp3 files are corrupted"},
+				},
+				Conclusion: Conclusion{
+					Text: "Working from home should be banned",
+				},
+			},
+			wantIssues: 2,
 		}}
 
 	for _, tc := range cases {
@@ -246,6 +274,42 @@ func TestQuantificationRequiredRule(t *testing.T) {
 				},
 			},
 			wantIssues: 2,
+		},
+		{
+			name: "Quantifier must be backed by a number in the same sentence",
+			argument: Argument{
+				Title: "Sentence-level quantification",
+				Premises: []Premise{
+					{Text: "Most of the 120 surveyed workers slack off. Productivity decreases at home.", Confidence: Medium},
+					{Text: "There was no increase in sick days", Confidence: Medium},
+				},
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "Numbers with units or prefixes count as numbers",
+			argument: Argument{
+				Title: "Numbers with units",
+				Premises: []Premise{
+					{Text: "Latency increased to 340ms after the release", Confidence: Medium},
+					{Text: "Traffic increased to 10k rps", Confidence: Medium},
+					{Text: "Latency increased 3x after the release", Confidence: Medium},
+					{Text: "Latency increased to 2 seconds at p99", Confidence: Medium},
+				},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "Names containing a digit are not numbers",
+			argument: Argument{
+				Title: "Names with digits",
+				Premises: []Premise{
+					{Text: "Latency increased on S3 after the release", Confidence: Medium},
+					{Text: "Traffic increased on k8s", Confidence: Medium},
+					{Text: "Errors increased over H2 and ipv6", Confidence: Medium},
+				},
+			},
+			wantIssues: 3,
 		}}
 	for _, tc := range cases {
 
@@ -308,6 +372,27 @@ func TestEmotionalLanguageDetector(t *testing.T) {
 				},
 			},
 			wantIssues: 2,
+		},
+		{
+			name: "Negated emotional language should not raise issues",
+			argument: Argument{
+				Title: "Negated emotional terms",
+				Premises: []Premise{
+					{Text: "The outage was not catastrophic", Confidence: Medium},
+					{Text: "Losing a day of work isn't terrible", Confidence: Low},
+				},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "Negation scope ends at the clause boundary",
+			argument: Argument{
+				Title: "Negation followed by emotional term",
+				Premises: []Premise{
+					{Text: "The outage was not long, but it was catastrophic", Confidence: Medium},
+				},
+			},
+			wantIssues: 1,
 		}}
 	for _, tc := range cases {
 
@@ -323,3 +408,257 @@ func TestEmotionalLanguageDetector(t *testing.T) {
 
 	}
 }
+
+func TestDuplicatePremiseRule(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		rule       DuplicatePremiseRule
+		argument   Argument
+		wantIssues int
+	}{{
+		name: "Reworded premise should raise one issue",
+		rule: DuplicatePremiseRule{},
+		argument: Argument{
+			Title: "Latency",
+			Premises: []Premise{
+				{Id: "P1", Text: "Our users complain about slow page loads"},
+				{Id: "P2", Text: "The user complains about the slow page load"},
+				{Id: "P3", Text: "The database runs on a single node"},
+			},
+		},
+		wantIssues: 1,
+	},
+		{
+			name: "Distinct premises should not raise issues",
+			rule: DuplicatePremiseRule{},
+			argument: Argument{
+				Title: "Latency",
+				Premises: []Premise{
+					{Id: "P1", Text: "Our users complain about slow page loads"},
+					{Id: "P2", Text: "The database runs on a single node"},
+				},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "Lower threshold should flag loosely related premises",
+			rule: DuplicatePremiseRule{Threshold: 0.3},
+			argument: Argument{
+				Title: "Latency",
+				Premises: []Premise{
+					{Id: "P1", Text: "Users complain about slow page loads"},
+					{Id: "P2", Text: "Users complain about the checkout"},
+				},
+			},
+			wantIssues: 1,
+		}}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := tc.rule.Check(tc.argument)
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing argument %q: got %d issue%s but we wanted %d", tc.argument.Title, got, plural(got), tc.wantIssues)
+			}
+		})
+
+	}
+}
+
+func TestQuotedEmotionalLanguage(t *testing.T) {
+
+	quotedPremise := Argument{
+		Title: "Quoted emotional term",
+		Premises: []Premise{
+			{Id: "P1", Text: "The CEO said the outage was 'catastrophic'"},
+		},
+	}
+	sourcedPremise := Argument{
+		Title: "Emotional term in a source quote",
+		Premises: []Premise{
+			{Id: "P1", Text: "The CEO described the outage in the all-hands", Sources: []Source{
+				{Title: "All-hands notes", Quote: "This outage was a terrible week for all of us"},
+			}},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		rule         EmotionalLanguageDetector
+		argument     Argument
+		wantIssues   int
+		wantSeverity Severity
+	}{
+		{name: "Quoted words are downgraded by default", rule: EmotionalLanguageDetector{}, argument: quotedPremise, wantIssues: 1, wantSeverity: SeverityWarning},
+		{name: "Quoted words can be skipped", rule: EmotionalLanguageDetector{Quotes: QuoteModeSkip}, argument: quotedPremise, wantIssues: 0},
+		{name: "Quoted words can be reported as the author's", rule: EmotionalLanguageDetector{Quotes: QuoteModeInclude}, argument: quotedPremise, wantIssues: 1, wantSeverity: SeverityError},
+		{name: "Source quotes are downgraded", rule: EmotionalLanguageDetector{}, argument: sourcedPremise, wantIssues: 1, wantSeverity: SeverityWarning},
+		{
+			name: "Apostrophes do not open a quote",
+			rule: EmotionalLanguageDetector{},
+			argument: Argument{
+				Title:    "Apostrophes",
+				Premises: []Premise{{Id: "P1", Text: "The team's week was terrible, and it's 'fine' to say so"}},
+			},
+			wantIssues:   1,
+			wantSeverity: SeverityError,
+		},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := tc.rule.Check(tc.argument)
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing argument %q: got %d issue%s but we wanted %d", tc.argument.Title, got, plural(got), tc.wantIssues)
+			}
+			if tc.wantIssues > 0 && issues[0].Severity != tc.wantSeverity {
+				t.Fatalf("Testing argument %q: got severity %s but we wanted %s", tc.argument.Title, issues[0].Severity, tc.wantSeverity)
+			}
+		})
+
+	}
+}
+
+func TestCustomLexicons(t *testing.T) {
+
+	lexicons, err := BuildLexicons(LexiconChanges{
+		LexiconVague: {
+			Add: []LexiconEntry{
+				{Term: "best-in-class"},
+				{Term: "seamless", Severity: SeverityInfo},
+				{Term: "robust", Regex: `\brobust(ly)?\b`, Severity: SeverityError},
+			},
+			Remove: []string{"some"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("building lexicons: %v", err)
+	}
+	rule := VaguenessDetector{Lexicons: lexicons}
+
+	cases := []struct {
+		name         string
+		text         string
+		wantIssues   int
+		wantSeverity Severity
+	}{
+		{name: "Added term is detected", text: "Our tooling is best-in-class", wantIssues: 1, wantSeverity: SeverityWarning},
+		{name: "Per-term severity is used", text: "The migration will be seamless", wantIssues: 1, wantSeverity: SeverityInfo},
+		{name: "Regex entry is detected with the most severe severity", text: "It scales robustly and seamless", wantIssues: 1, wantSeverity: SeverityError},
+		{name: "Removed term is not detected", text: "Some workers slack off", wantIssues: 0},
+		{name: "Built-in terms are kept", text: "Maybe workers slack off", wantIssues: 1, wantSeverity: SeverityWarning},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := rule.Check(Argument{Premises: []Premise{{Id: "P1", Text: tc.text}}})
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing premise %q: got %d issue%s but we wanted %d", tc.text, got, plural(got), tc.wantIssues)
+			}
+			if tc.wantIssues > 0 && issues[0].Severity != tc.wantSeverity {
+				t.Fatalf("Testing premise %q: got severity %s but we wanted %s", tc.text, issues[0].Severity, tc.wantSeverity)
+			}
+		})
+
+	}
+
+	scoped, err := BuildLexicons(LexiconChanges{"it.vague": {Add: []LexiconEntry{{Term: "robusto"}}}})
+	if err != nil {
+		t.Fatalf("building lexicons: %v", err)
+	}
+	italian := Argument{Language: "it", Premises: []Premise{{Id: "P1", Text: "Il sistema è robusto"}}}
+	english := Argument{Premises: []Premise{{Id: "P1", Text: "The system is robusto"}}}
+	if got := len(VaguenessDetector{Lexicons: scoped}.Check(italian)); got != 1 {
+		t.Fatalf("language-scoped term: got %d issue%s but we wanted 1", got, plural(got))
+	}
+	if got := len(VaguenessDetector{Lexicons: scoped}.Check(english)); got != 0 {
+		t.Fatalf("language-scoped term leaked into English: got %d issue%s but we wanted 0", got, plural(got))
+	}
+
+	if _, err := BuildLexicons(LexiconChanges{"weasel": {Add: []LexiconEntry{{Term: "robust"}}}}); err == nil {
+		t.Fatalf("expected an error for an unknown lexicon")
+	}
+	if _, err := BuildLexicons(LexiconChanges{LexiconVague: {Add: []LexiconEntry{{Regex: `(`}}}}); err == nil {
+		t.Fatalf("expected an error for an invalid regex")
+	}
+}
+
+func TestMultilingualLexicons(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		rule       Rule
+		argument   Argument
+		wantIssues int
+	}{
+		{
+			name: "Italian emotional language is detected",
+			rule: EmotionalLanguageDetector{},
+			argument: Argument{
+				Title:    "Migrazione del database",
+				Premises: []Premise{{Id: "P1", Text: "Il downtime della settimana scorsa è stato catastrofico per il team"}},
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "Italian negation is honoured",
+			rule: EmotionalLanguageDetector{},
+			argument: Argument{
+				Title:    "Migrazione del database",
+				Premises: []Premise{{Id: "P1", Text: "Il downtime non è stato catastrofico per il team"}},
+			},
+			wantIssues: 0,
+		},
+		{
+			name: "German vagueness with non-ASCII letters is detected",
+			rule: VaguenessDetector{},
+			argument: Argument{
+				Title:    "Umzug in die Cloud",
+				Premises: []Premise{{Id: "P1", Text: "Jeder weiß, dass die Kosten mit der Cloud sinken"}},
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "Explicit language selects the pack",
+			rule: QuantificationRequiredRule{},
+			argument: Argument{
+				Title:    "Latenza",
+				Language: "it",
+				Premises: []Premise{{Id: "P1", Text: "La latenza è in aumento"}},
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "English lexicons do not apply to other languages",
+			rule: EmotionalLanguageDetector{},
+			argument: Argument{
+				Title:    "Latenza",
+				Language: "it",
+				Premises: []Premise{{Id: "P1", Text: "La latenza è terrible"}},
+			},
+			wantIssues: 0,
+		},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := tc.rule.Check(tc.argument)
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("Testing argument %q: got %d issue%s but we wanted %d", tc.argument.Title, got, plural(got), tc.wantIssues)
+			}
+		})
+
+	}
+}
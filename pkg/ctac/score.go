@@ -0,0 +1,125 @@
+package ctac
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScoreWeights are the relative weights of the parts of an argument's
+// score. When all are zero the defaults are used.
+type ScoreWeights struct {
+	// Issues rates the issues found, taking points off by severity.
+	Issues float64 `yaml:"issues" json:"issues"`
+	// Premises rates the number of premises, up to three.
+	Premises float64 `yaml:"premises" json:"premises"`
+	// Confidence rates the confidence of the premises.
+	Confidence float64 `yaml:"confidence" json:"confidence"`
+	// Sources rates the share of premises citing a source.
+	Sources float64 `yaml:"sources" json:"sources"`
+	// Counterarguments rates the share of alternatives whose cons or
+	// reason for rejection are stated. It does not apply to an argument
+	// without alternatives, which is scored on the other parts alone.
+	Counterarguments float64 `yaml:"counterarguments" json:"counterarguments"`
+}
+
+// DefaultScoreWeights are used when the config sets no weights.
+var DefaultScoreWeights = ScoreWeights{Issues: 50, Premises: 10, Confidence: 10, Sources: 20, Counterarguments: 10}
+
+type ScoreConfig struct {
+	Weights ScoreWeights `yaml:"weights" json:"weights"`
+}
+
+func (w ScoreWeights) validate() error {
+	for _, weight := range []struct {
+		name  string
+		value float64
+	}{{"issues", w.Issues}, {"premises", w.Premises}, {"confidence", w.Confidence}, {"sources", w.Sources}, {"counterarguments", w.Counterarguments}} {
+		if weight.value < 0 {
+			return fmt.Errorf("score.weights.%s must not be negative, got %v", weight.name, weight.value)
+		}
+	}
+	return nil
+}
+
+// premisesForScore is the number of premises that earns full marks.
+const premisesForScore = 3
+
+var confidenceScores = map[Confidence]float64{Low: 1.0 / 3, Medium: 2.0 / 3, High: 1}
+
+// ScoreArgument rates an argument from 0 to 100, combining the issues found
+// with the number and confidence of its premises, how many cite sources and
+// how many of its alternatives are answered. Zero weights use the defaults.
+func ScoreArgument(argument Argument, issues []Issue, weights ScoreWeights) int {
+	if weights == (ScoreWeights{}) {
+		weights = DefaultScoreWeights
+	}
+
+	issuePoints := 100.0
+	for _, issue := range issues {
+		switch issue.Severity {
+		case SeverityError:
+			issuePoints -= 15
+		case SeverityWarning:
+			issuePoints -= 5
+		default:
+			issuePoints--
+		}
+	}
+
+	var confidence, sourced float64
+	for _, p := range argument.Premises {
+		confidence += confidenceScores[p.Confidence]
+		if len(p.Sources) > 0 {
+			sourced++
+		}
+	}
+	var answered float64
+	for _, alternative := range argument.Alternatives {
+		if len(alternative.Cons) > 0 || alternative.RejectedBecause != "" {
+			answered++
+		}
+	}
+
+	type part struct{ weight, value float64 }
+	parts := []part{
+		{weights.Issues, math.Max(issuePoints, 0) / 100},
+		{weights.Premises, math.Min(float64(len(argument.Premises)), premisesForScore) / premisesForScore},
+		{weights.Confidence, share(confidence, len(argument.Premises))},
+		{weights.Sources, share(sourced, len(argument.Premises))},
+	}
+	if len(argument.Alternatives) > 0 {
+		parts = append(parts, part{weights.Counterarguments, answered / float64(len(argument.Alternatives))})
+	}
+	var total, score float64
+	for _, p := range parts {
+		total += p.weight
+		score += p.weight * p.value
+	}
+	if total == 0 {
+		return 100
+	}
+	return int(math.Round(100 * score / total))
+}
+
+func share(count float64, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return count / float64(of)
+}
+
+// Grade turns a score into a letter from A (90 and above) to F (below 60).
+func Grade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
@@ -0,0 +1,85 @@
+package ctac
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScoreArgument(t *testing.T) {
+
+	sourced := []Source{{Title: "Capacity dashboard"}}
+	strong := Argument{
+		Premises: []Premise{
+			{Id: "P1", Text: "The database is at 80% capacity", Confidence: High, Sources: sourced},
+			{Id: "P2", Text: "Traffic grows 5% per month", Confidence: High, Sources: sourced},
+			{Id: "P3", Text: "Capacity runs out in four months", Confidence: High, Sources: sourced},
+		},
+		Alternatives: []Alternative{{Name: "Do nothing", RejectedBecause: "We run out of capacity"}},
+	}
+	weak := Argument{
+		Premises: []Premise{{Id: "P1", Text: "The database feels slow", Confidence: Low}},
+	}
+	unanswered := weak
+	unanswered.Alternatives = []Alternative{{Name: "Add an index"}}
+	warning := []Issue{{RuleID: "TEST", Severity: SeverityWarning}}
+
+	cases := []struct {
+		name     string
+		argument Argument
+		issues   []Issue
+		weights  ScoreWeights
+		want     int
+	}{
+		{name: "Full marks", argument: strong, want: 100},
+		{name: "Issues take points off", argument: strong, issues: append(warning, Issue{Severity: SeverityError}), want: 90},
+		// 50 for no issues, 10/3 for one premise of three, 10/3 for low
+		// confidence, out of 90 as counterarguments do not apply.
+		{name: "One low confidence premise without sources", argument: weak, want: 63},
+		{name: "Unanswered alternatives count", argument: unanswered, want: 57},
+		{name: "Only counterarguments weigh, without alternatives", argument: weak, weights: ScoreWeights{Counterarguments: 1}, want: 100},
+		{name: "Only issues weigh", argument: weak, issues: warning, weights: ScoreWeights{Issues: 1}, want: 95},
+		{name: "Only sources weigh", argument: weak, weights: ScoreWeights{Sources: 1}, want: 0},
+		{name: "Empty argument", argument: Argument{}, want: 56},
+	}
+	for _, tc := range cases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ScoreArgument(tc.argument, tc.issues, tc.weights); got != tc.want {
+				t.Fatalf("got score %d but we wanted %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGrade(t *testing.T) {
+
+	for score, want := range map[int]string{100: "A", 90: "A", 89: "B", 80: "B", 75: "C", 60: "D", 59: "F", 0: "F"} {
+		if got := Grade(score); got != want {
+			t.Errorf("got grade %s for %d but we wanted %s", got, score, want)
+		}
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+
+	argument := Argument{Title: "Weights", Premises: []Premise{{Id: "P1", Text: "Queues decouple services", Confidence: High}}}
+	config := &Config{Score: ScoreConfig{Weights: ScoreWeights{Confidence: 1}}}
+	if err := config.Compile("."); err != nil {
+		t.Fatalf("compiling config: %v", err)
+	}
+
+	report := NewAnalyzer(WithConfig(config)).Analyze(context.Background(), argument)
+	if report.Score != 100 || report.Grade != "A" {
+		t.Fatalf("got score %d (%s) but we wanted 100 (A) from the config weights", report.Score, report.Grade)
+	}
+	report = NewAnalyzer(WithConfig(config), WithScoreWeights(ScoreWeights{Sources: 1})).Analyze(context.Background(), argument)
+	if report.Score != 0 || report.Grade != "F" {
+		t.Fatalf("got score %d (%s) but we wanted 0 (F) from the explicit weights", report.Score, report.Grade)
+	}
+
+	invalid := &Config{Score: ScoreConfig{Weights: ScoreWeights{Issues: 1, Sources: -1}}}
+	if err := invalid.Compile("."); err == nil || !strings.Contains(err.Error(), "score.weights.sources") {
+		t.Fatalf("got error %v but we wanted one about score.weights.sources", err)
+	}
+}
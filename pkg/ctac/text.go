@@ -0,0 +1,306 @@
+package ctac
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The text-analysis layer shared by the lexicon rules. Text is split into
+// word tokens and sentences; each token records whether it falls inside the
+// scope of a negation ("this is not catastrophic"), whether it sits next
+// to a number ("some 42% of users") and whether it is quoted ("the CEO said
+// it was 'catastrophic'"), so rules can skip matches that would otherwise be
+// false positives.
+
+var regexToken = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’.,][\p{L}\p{N}]+)*`)
+
+// negationScope is the number of tokens after a negation cue that are
+// considered negated, unless the clause ends first.
+const negationScope = 4
+
+var clauseBreakers = map[string]bool{
+	"but": true, "however": true, "although": true, "though": true, "yet": true, "whereas": true,
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true, "in": true,
+	"on": true, "at": true, "for": true, "by": true, "with": true, "is": true, "are": true, "was": true,
+	"were": true, "be": true, "been": true, "it": true, "this": true, "that": true, "these": true,
+	"those": true, "as": true, "from": true, "our": true, "we": true, "they": true, "their": true,
+}
+
+type token struct {
+	Text       string // lower-cased
+	Start      int    // byte offset in the analysed text
+	End        int
+	Sentence   int
+	Number     bool
+	Negated    bool
+	NearNumber bool
+	Quoted     bool
+}
+
+type analysedText struct {
+	Text      string
+	Tokens    []token
+	Sentences int
+}
+
+// textMatch is a lexicon match in an analysed text.
+type textMatch struct {
+	Start      int
+	End        int
+	Sentence   int
+	Negated    bool
+	NearNumber bool
+	Quoted     bool
+}
+
+func isNegationCue(word string, negations map[string]bool) bool {
+	return negations[word] || strings.HasSuffix(word, "n't") || strings.HasSuffix(word, "n’t")
+}
+
+// regexNumber matches tokens that count as numbers: figures, with an optional
+// unit or suffix such as "340ms", "10k" or "3x", and percentiles such as
+// "p99". Names that merely contain a digit, such as "S3" or "k8s", do not.
+var regexNumber = regexp.MustCompile(`^(?:\d[\d.,]*[\p{L}%]*|p\d[\d.]*)$`)
+
+// isNumber reports whether a token counts as a number.
+func isNumber(word string) bool {
+	return regexNumber.MatchString(word)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// quotedSpans returns the byte ranges enclosed in quotation marks. Single
+// quotes only count when they open before a word and close after one, so
+// apostrophes ("don't", "users'") are not mistaken for quotes. An unclosed
+// quotation mark is ignored.
+func quotedSpans(text string) [][2]int {
+	var spans [][2]int
+	open := -1
+	var closer rune
+
+	for i, r := range text {
+		if open >= 0 {
+			if r != closer {
+				continue
+			}
+			if closer == '\'' || closer == '’' {
+				next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+				if isWordRune(next) {
+					continue
+				}
+			}
+			spans = append(spans, [2]int{open, i})
+			open = -1
+			continue
+		}
+
+		switch r {
+		case '"':
+			open, closer = i, '"'
+		case '“':
+			open, closer = i, '”'
+		case '«':
+			open, closer = i, '»'
+		case '\'', '‘':
+			previous, _ := utf8.DecodeLastRuneInString(text[:i])
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if !isWordRune(previous) && isWordRune(next) {
+				open, closer = i, '’'
+				if r == '\'' {
+					closer = '\''
+				}
+			}
+		}
+	}
+	return spans
+}
+
+// analyseText tokenises text, treating the given words as negation cues.
+// A nil negations uses the cues of the default language.
+func analyseText(text string, negations map[string]bool) analysedText {
+	if negations == nil {
+		negations = defaultLexicons.languages[DefaultLanguage].negations
+	}
+	analysed := analysedText{Text: text}
+	quotes := quotedSpans(text)
+	sentence := 0
+	negationLeft := 0
+	previousEnd := 0
+
+	for _, loc := range regexToken.FindAllStringIndex(text, -1) {
+		gap := text[previousEnd:loc[0]]
+		if strings.ContainsAny(gap, ".!?") && len(analysed.Tokens) > 0 {
+			sentence++
+			negationLeft = 0
+		}
+		if strings.ContainsAny(gap, ",;:()") {
+			negationLeft = 0
+		}
+
+		word := strings.ToLower(text[loc[0]:loc[1]])
+		if clauseBreakers[word] {
+			negationLeft = 0
+		}
+
+		quoted := false
+		for _, span := range quotes {
+			if loc[0] > span[0] && loc[0] < span[1] {
+				quoted = true
+				break
+			}
+		}
+
+		analysed.Tokens = append(analysed.Tokens, token{
+			Text:     word,
+			Start:    loc[0],
+			End:      loc[1],
+			Sentence: sentence,
+			Number:   isNumber(word),
+			Negated:  negationLeft > 0,
+			Quoted:   quoted,
+		})
+
+		if negationLeft > 0 {
+			negationLeft--
+		}
+		if isNegationCue(word, negations) {
+			negationLeft = negationScope
+		}
+		previousEnd = loc[1]
+	}
+
+	tokens := analysed.Tokens
+	for i := range tokens {
+		before := i > 0 && tokens[i-1].Number && tokens[i-1].Sentence == tokens[i].Sentence
+		after := i+1 < len(tokens) && tokens[i+1].Number && tokens[i+1].Sentence == tokens[i].Sentence
+		tokens[i].NearNumber = before || after
+	}
+
+	if len(tokens) > 0 {
+		analysed.Sentences = sentence + 1
+	}
+	return analysed
+}
+
+// find turns the byte ranges of lexicon matches into text matches. A match is negated when its
+// first word is in the scope of a negation, and near a number when any of its
+// words is, or is next to, a number. Matches that cover no word (such as "%")
+// take their flags from the word just before them.
+func (t analysedText) find(locs [][]int) []textMatch {
+	var matches []textMatch
+	for _, loc := range locs {
+		match := textMatch{Start: loc[0], End: loc[1]}
+		covered := false
+		for _, tok := range t.Tokens {
+			if tok.End <= loc[0] || tok.Start >= loc[1] {
+				continue
+			}
+			if !covered {
+				match.Sentence = tok.Sentence
+				match.Negated = tok.Negated
+				match.Quoted = tok.Quoted
+				covered = true
+			}
+			match.NearNumber = match.NearNumber || tok.Number || tok.NearNumber
+		}
+		if !covered {
+			for i := len(t.Tokens) - 1; i >= 0; i-- {
+				if tok := t.Tokens[i]; tok.End <= loc[0] {
+					match.Sentence = tok.Sentence
+					match.Negated = tok.Negated
+					match.Quoted = tok.Quoted
+					match.NearNumber = tok.Number || tok.NearNumber
+					break
+				}
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// contains reports whether phrase matches the text at a position accepted by keep.
+func (t analysedText) contains(phrase lexiconPhrase, keep func(textMatch) bool) bool {
+	for _, m := range t.find(phrase.locate(t.Text)) {
+		if keep(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// sentenceHasNumber reports whether sentence i contains a number.
+func (t analysedText) sentenceHasNumber(i int) bool {
+	for _, tok := range t.Tokens {
+		if tok.Sentence == i && tok.Number {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lower-cased words, dropping punctuation.
+func words(text string) []string {
+	tokens := analyseText(text, nil).Tokens
+	result := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		result = append(result, tok.Text)
+	}
+	return result
+}
+
+// stem strips common English suffixes so that "users" and "user" normalise
+// to a comparable form. It is deliberately crude.
+func stem(word string) string {
+	word = strings.TrimSuffix(word, "'s")
+	for _, suffix := range []string{"ational", "ization", "ingly", "ments", "ment", "ness", "ing", "ies", "ied", "ed", "ly", "es", "s", "e"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// stemSet returns the set of stems of the non stop-words in text.
+func stemSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range words(text) {
+		if stopWords[w] {
+			continue
+		}
+		set[stem(w)] = true
+	}
+	return set
+}
+
+// jaccard returns the Jaccard similarity of two sets, between 0 and 1.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// affirmed keeps matches that are not negated.
+func affirmed(m textMatch) bool {
+	return !m.Negated
+}
+
+// affirmedWithoutNumber keeps matches that are neither negated nor qualified
+// by an adjacent number.
+func affirmedWithoutNumber(m textMatch) bool {
+	return !m.Negated && !m.NearNumber
+}
@@ -0,0 +1,73 @@
+package ctac
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ArgumentFile is an argument and the file it was read from.
+type ArgumentFile struct {
+	Path     string
+	Argument Argument
+}
+
+// LoadDir loads the arguments of every YAML, JSON, TOML and Markdown file
+// under dir, skipping hidden directories. Files that are not arguments,
+// such as config files or Markdown without an argument, are skipped; files
+// that fail to load are returned as errors without stopping the walk.
+func LoadDir(dir string) ([]ArgumentFile, []error) {
+	var files []ArgumentFile
+	var errs []error
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := FormatFromPath(path); !ok || isCtacFile(entry.Name()) {
+			return nil
+		}
+
+		arguments, err := LoadArguments(path)
+		if errors.Is(err, errNoMarkdownArgument) {
+			return nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			return nil
+		}
+		for _, argument := range arguments {
+			if argument.Title == "" && len(argument.Premises) == 0 && argument.Conclusion.Text == "" {
+				continue
+			}
+			files = append(files, ArgumentFile{Path: path, Argument: argument})
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return files, errs
+}
+
+// isCtacFile reports whether name is one of the default config, ignore or
+// assumptions files, which are not arguments.
+func isCtacFile(name string) bool {
+	for _, names := range [][]string{defaultConfigFiles, defaultIgnoreFiles, defaultAssumptionsFiles} {
+		for _, n := range names {
+			if name == n {
+				return true
+			}
+		}
+	}
+	return false
+}
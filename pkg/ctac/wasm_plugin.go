@@ -0,0 +1,208 @@
+package ctac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// WebAssembly rule plugins.
+//
+// A plugin is a .wasm module exporting:
+//
+//	memory                       its linear memory
+//	alloc(size i32) i32          returns a buffer of size bytes for the input
+//	check(ptr i32, len i32) i64  reads the argument as JSON from ptr and
+//	                             returns (resultPtr << 32 | resultLen) of a
+//	                             JSON array of issues
+//
+// Modules may import WASI (wasi_snapshot_preview1) but get no file system,
+// environment or network access. Every check runs in a fresh module
+// instance, so plugins keep no state between arguments.
+
+const (
+	defaultPluginTimeout       = 5 * time.Second
+	defaultPluginMemoryLimitMB = 16
+	wasmPageSize               = 64 * 1024
+)
+
+// PluginSpec declares a WebAssembly rule plugin in the config file.
+type PluginSpec struct {
+	ID            string        `yaml:"id" json:"id"`
+	Path          string        `yaml:"path" json:"path"`
+	Timeout       time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+	MemoryLimitMB int           `yaml:"memoryLimitMB" json:"memoryLimitMB,omitempty"`
+}
+
+// WasmRule is a rule backed by a WebAssembly plugin.
+type WasmRule struct {
+	id       string
+	timeout  time.Duration
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// LoadWasmRule compiles the plugin at spec.Path, resolved relative to baseDir.
+func LoadWasmRule(ctx context.Context, spec PluginSpec, baseDir string) (*WasmRule, error) {
+	if spec.Path == "" {
+		return nil, fmt.Errorf("plugin %s: path is required", spec.ID)
+	}
+	path := spec.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", spec.ID, err)
+	}
+	return NewWasmRule(ctx, spec, wasm)
+}
+
+// NewWasmRule compiles a plugin from its WebAssembly binary.
+func NewWasmRule(ctx context.Context, spec PluginSpec, wasm []byte) (*WasmRule, error) {
+	if spec.ID == "" {
+		return nil, fmt.Errorf("plugin needs an id")
+	}
+	timeout := spec.Timeout
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	memoryLimitMB := spec.MemoryLimitMB
+	if memoryLimitMB <= 0 {
+		memoryLimitMB = defaultPluginMemoryLimitMB
+	}
+
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(memoryLimitMB * 1024 * 1024 / wasmPageSize)).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("plugin %s: %w", spec.ID, err)
+	}
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("plugin %s: %w", spec.ID, err)
+	}
+	for _, name := range []string{"alloc", "check"} {
+		if _, ok := compiled.ExportedFunctions()[name]; !ok {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("plugin %s: module does not export %q", spec.ID, name)
+		}
+	}
+	if _, ok := compiled.ExportedMemories()["memory"]; !ok {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("plugin %s: module does not export its memory", spec.ID)
+	}
+
+	return &WasmRule{id: spec.ID, timeout: timeout, runtime: runtime, compiled: compiled}, nil
+}
+
+func (rule *WasmRule) ID() string {
+	return rule.id
+}
+
+// Close releases the plugin's runtime.
+func (rule *WasmRule) Close(ctx context.Context) error {
+	return rule.runtime.Close(ctx)
+}
+
+func (rule *WasmRule) Check(argument Argument) []Issue {
+	issues, err := rule.CheckContext(context.Background(), argument)
+	if err != nil {
+		return []Issue{ruleErrorIssue(&RuleError{RuleID: rule.id, Err: err})}
+	}
+	return issues
+}
+
+// CheckContext runs the plugin against the argument, stopping it when ctx
+// is done or the plugin's timeout expires.
+func (rule *WasmRule) CheckContext(ctx context.Context, argument Argument) ([]Issue, error) {
+	ctx, cancel := context.WithTimeout(ctx, rule.timeout)
+	defer cancel()
+
+	output, err := rule.call(ctx, argument)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin timed out after %s", rule.timeout)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return decodePluginIssues(rule.id, output)
+}
+
+func (rule *WasmRule) call(ctx context.Context, argument Argument) ([]byte, error) {
+	input, err := json.Marshal(argument)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := rule.runtime.InstantiateModule(ctx, rule.compiled, wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, fmt.Errorf("instantiating plugin: %w", err)
+	}
+	defer module.Close(ctx)
+
+	results, err := module.ExportedFunction("alloc").Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("alloc: %w", err)
+	}
+	inputPtr := uint32(results[0])
+	if !module.Memory().Write(inputPtr, input) {
+		return nil, fmt.Errorf("alloc returned an out of range buffer (%d bytes at %d)", len(input), inputPtr)
+	}
+
+	results, err = module.ExportedFunction("check").Call(ctx, uint64(inputPtr), uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("check: %w", err)
+	}
+	outputPtr, outputLen := uint32(results[0]>>32), uint32(results[0])
+	output, ok := module.Memory().Read(outputPtr, outputLen)
+	if !ok {
+		return nil, fmt.Errorf("check returned an out of range result (%d bytes at %d)", outputLen, outputPtr)
+	}
+	return append([]byte(nil), output...), nil
+}
+
+// decodePluginIssues parses the JSON array of issues returned by a plugin.
+func decodePluginIssues(ruleID string, output []byte) ([]Issue, error) {
+	var issues []Issue
+	if err := json.Unmarshal(output, &issues); err != nil {
+		return nil, fmt.Errorf("malformed output: %w", err)
+	}
+	return validateExternalIssues(ruleID, issues)
+}
+
+// validateExternalIssues checks the issues returned by a rule running
+// outside ctac. Issues without a RuleID get the rule's ID; no issue may
+// claim a built-in ID.
+func validateExternalIssues(ruleID string, issues []Issue) ([]Issue, error) {
+	for i := range issues {
+		if issues[i].RuleID == "" {
+			issues[i].RuleID = ruleID
+		}
+		if err := checkExternalRuleID(issues[i].RuleID, nil); err != nil {
+			return nil, fmt.Errorf("issue %d: %w", i, err)
+		}
+		switch issues[i].Severity {
+		case SeverityInfo, SeverityWarning, SeverityError:
+		case "":
+			issues[i].Severity = SeverityWarning
+		default:
+			return nil, fmt.Errorf("issue %d: unknown severity %q", i, issues[i].Severity)
+		}
+	}
+	return issues, nil
+}
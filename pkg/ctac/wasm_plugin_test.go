@@ -0,0 +1,162 @@
+package ctac
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The test plugins are assembled by hand so the tests need no wasm toolchain.
+
+func uleb128(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if v == 0 {
+			return out
+		}
+	}
+}
+
+func sleb128(v int64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func wasmVector(items ...[]byte) []byte {
+	out := uleb128(uint64(len(items)))
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func wasmSection(id byte, content []byte) []byte {
+	return append(append([]byte{id}, uleb128(uint64(len(content)))...), content...)
+}
+
+func wasmName(name string) []byte {
+	return append(uleb128(uint64(len(name))), name...)
+}
+
+func wasmBody(code ...byte) []byte {
+	body := append([]byte{0x00}, code...) // no locals
+	return append(uleb128(uint64(len(body))), body...)
+}
+
+// testPlugin builds a module whose check returns output, or runs checkCode
+// when it is set.
+func testPlugin(output string, checkCode []byte) []byte {
+	const inputAt, outputAt = 2048, 1024
+
+	if checkCode == nil {
+		checkCode = append([]byte{0x42}, sleb128(int64(outputAt)<<32|int64(len(output)))...) // i64.const
+	}
+	checkCode = append(checkCode, 0x0b)
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, wasmSection(1, wasmVector(
+		[]byte{0x60, 0x01, 0x7f, 0x01, 0x7f},       // (i32) -> i32
+		[]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e}, // (i32, i32) -> i64
+	))...)
+	module = append(module, wasmSection(3, wasmVector([]byte{0x00}, []byte{0x01}))...)
+	module = append(module, wasmSection(5, wasmVector([]byte{0x00, 0x01}))...)
+	module = append(module, wasmSection(7, wasmVector(
+		append(wasmName("memory"), 0x02, 0x00),
+		append(wasmName("alloc"), 0x00, 0x00),
+		append(wasmName("check"), 0x00, 0x01),
+	))...)
+	module = append(module, wasmSection(10, wasmVector(
+		wasmBody(append(append([]byte{0x41}, sleb128(inputAt)...), 0x0b)...), // i32.const
+		wasmBody(checkCode...),
+	))...)
+	segment := append([]byte{0x00, 0x41}, sleb128(outputAt)...)
+	segment = append(append(segment, 0x0b), wasmName(output)...)
+	module = append(module, wasmSection(11, wasmVector(segment))...)
+	return module
+}
+
+func TestWasmRule(t *testing.T) {
+
+	ctx := context.Background()
+	argument := Argument{Title: "Plugins", Premises: []Premise{{Id: "P1", Text: "Plugins are useful"}}}
+
+	cases := []struct {
+		name       string
+		plugin     []byte
+		wantIssues int
+		wantErr    string
+	}{
+		{
+			name:       "Issues are returned with the plugin ID",
+			plugin:     testPlugin(`[{"Severity":"warning","Message":"from wasm","Hint":"hint"},{"Message":"second"}]`, nil),
+			wantIssues: 2,
+		},
+		{name: "No issues", plugin: testPlugin(`[]`, nil), wantIssues: 0},
+		{name: "Malformed output is an error", plugin: testPlugin(`not json`, nil), wantErr: "malformed output"},
+		{name: "Built-in IDs cannot be claimed", plugin: testPlugin(`[{"RuleID":"CTAC001_MISSING_PREMISES"}]`, nil), wantErr: "reserved"},
+		{name: "Traps are errors", plugin: testPlugin(``, []byte{0x00}), wantErr: "unreachable"},
+		{name: "Endless loops time out", plugin: testPlugin(``, []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00}), wantErr: "timed out"},
+	}
+	for _, tc := range cases {
+
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := NewWasmRule(ctx, PluginSpec{ID: "ACME001_PLUGIN", Timeout: 200 * time.Millisecond}, tc.plugin)
+			if err != nil {
+				t.Fatalf("loading plugin: %v", err)
+			}
+			defer rule.Close(ctx)
+
+			issues, err := rule.CheckContext(ctx, argument)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v but we wanted one containing %q", err, tc.wantErr)
+				}
+				if checked := rule.Check(argument); len(checked) != 1 || checked[0].Severity != SeverityError {
+					t.Fatalf("Check should report the failure as a single error issue, got %v", checked)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checking: %v", err)
+			}
+			if got := len(issues); got != tc.wantIssues {
+				t.Fatalf("got %d issue%s but we wanted %d", got, plural(got), tc.wantIssues)
+			}
+			for _, issue := range issues {
+				if issue.RuleID != "ACME001_PLUGIN" {
+					t.Fatalf("got rule ID %q but we wanted the plugin ID", issue.RuleID)
+				}
+			}
+		})
+	}
+}
+
+func TestWasmRuleLoadErrors(t *testing.T) {
+
+	ctx := context.Background()
+	if _, err := NewWasmRule(ctx, PluginSpec{ID: "ACME001_PLUGIN"}, []byte("not wasm")); err == nil {
+		t.Fatalf("expected an error for an invalid module")
+	}
+
+	config := Config{Plugins: []PluginSpec{{ID: "CTAC100_PLUGIN", Path: "plugin.wasm"}}}
+	if err := config.Compile(t.TempDir()); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Fatalf("got error %v but we wanted one about the reserved CTAC prefix", err)
+	}
+}